@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	docs "trellode-go/docs"
 	"trellode-go/internal/api"
+	"trellode-go/internal/automation"
 	"trellode-go/internal/middlewares"
+	"trellode-go/internal/ranking"
+	"trellode-go/internal/recurring"
+	"trellode-go/internal/reminder"
 
 	"trellode-go/internal/utils/config"
 
@@ -38,7 +43,35 @@ func main() {
 
 	s.Routes()
 
-	err := r.Run()
+	// send reminders of due cards in the background
+	scheduler, err := reminder.NewSchedulerFromEnv(db, log)
+	if err != nil {
+		log.Fatal("Invalid reminder configuration: " + err.Error())
+	}
+	go scheduler.Run(context.Background())
+
+	// run the rules triggered by passed due dates in the background
+	automationScheduler, err := automation.NewSchedulerFromEnv(db, log)
+	if err != nil {
+		log.Fatal("Invalid automation configuration: " + err.Error())
+	}
+	go automationScheduler.Run(context.Background())
+
+	// copy recurring cards in the background
+	recurringScheduler, err := recurring.NewSchedulerFromEnv(db, log)
+	if err != nil {
+		log.Fatal("Invalid recurring cards configuration: " + err.Error())
+	}
+	go recurringScheduler.Run(context.Background())
+
+	// rebalance card, list and checklist item ranks in the background
+	rebalancer, err := ranking.NewRebalancerFromEnv(db, log)
+	if err != nil {
+		log.Fatal("Invalid rank rebalancing configuration: " + err.Error())
+	}
+	go rebalancer.Run(context.Background())
+
+	err = r.Run()
 	if err != nil {
 		return
 	} // listen and serve on 0.0.0.0:8080 (for windows "localhost:8080")
@@ -31,8 +31,10 @@ CREATE TABLE backgrounds (
 CREATE TABLE boards (
     id CHAR(36) DEFAULT UUID() PRIMARY KEY,
     user_id CHAR(36) NOT NULL,
+    workspace_id CHAR(36) NULL,
     title VARCHAR(255) NOT NULL,
     background_id CHAR(36),
+    is_template TINYINT(1) NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     archived_at TIMESTAMP NULL,
@@ -46,7 +48,11 @@ CREATE TABLE lists (
     id CHAR(36) DEFAULT UUID() PRIMARY KEY,
     board_id CHAR(36) NOT NULL,
     title VARCHAR(255) NOT NULL,
-    position INT NOT NULL,
+    rank_key VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+    is_done TINYINT(1) NOT NULL DEFAULT 0,
+    refuse_blocked TINYINT(1) NOT NULL DEFAULT 0,
+    wip_limit INT NOT NULL DEFAULT 0,
+    wip_hard TINYINT(1) NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     archived_at TIMESTAMP NULL
@@ -59,7 +65,15 @@ CREATE TABLE cards (
     list_id CHAR(36) NOT NULL,
     title VARCHAR(255) NOT NULL,
     description TEXT,
-    position INT NOT NULL,
+    rank_key VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+    version INT NOT NULL DEFAULT 0,
+    start_at TIMESTAMP NULL,
+    due_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    cover_color VARCHAR(7) NOT NULL DEFAULT '',
+    cover_attachment_id CHAR(36) NULL,
+    cover_thumbnail MEDIUMTEXT NULL,
+    cover_text_color VARCHAR(7) NOT NULL DEFAULT '',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     archived_at TIMESTAMP NULL
@@ -70,10 +84,24 @@ CREATE TABLE cards (
 CREATE TABLE comments (
     id CHAR(36) DEFAULT UUID() PRIMARY KEY,
     card_id CHAR(36) NOT NULL,
+    parent_id CHAR(36) NULL DEFAULT NULL,
     user_id CHAR(36) NOT NULL,
     content TEXT NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    edited_at DATETIME NULL DEFAULT NULL,
+    deleted_at DATETIME NULL DEFAULT NULL,
+    INDEX (card_id, parent_id, created_at)
+);
+
+-- Comment revisions table
+CREATE TABLE comment_revisions (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    comment_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (comment_id)
 );
 
 CREATE TABLE checklists (
@@ -90,8 +118,177 @@ CREATE TABLE checklistitems (
     id CHAR(36) DEFAULT UUID() PRIMARY KEY,
     checklist_id CHAR(36) NOT NULL,
     title VARCHAR(255) NOT NULL,
-    position INT NOT NULL,
+    rank_key VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
     checked TINYINT(1) NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
+
+-- Board members table
+CREATE TABLE board_members (
+    board_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_id, user_id)
+);
+
+-- Workspaces table
+CREATE TABLE workspaces (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    default_role VARCHAR(16) NOT NULL DEFAULT 'viewer',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Workspace members table
+CREATE TABLE workspace_members (
+    workspace_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- Labels table
+CREATE TABLE labels (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    board_id CHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    color VARCHAR(7) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Card labels table
+CREATE TABLE card_labels (
+    card_id CHAR(36) NOT NULL,
+    label_id CHAR(36) NOT NULL,
+    PRIMARY KEY (card_id, label_id)
+);
+
+-- Custom fields table
+CREATE TABLE custom_fields (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    board_id CHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    options TEXT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Custom field values table
+CREATE TABLE custom_field_values (
+    card_id CHAR(36) NOT NULL,
+    custom_field_id CHAR(36) NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (card_id, custom_field_id)
+);
+
+-- Sent reminders table
+CREATE TABLE sent_reminders (
+    card_id CHAR(36) NOT NULL,
+    offset_seconds INT NOT NULL,
+    due_at DATETIME NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (card_id, offset_seconds, due_at)
+);
+
+-- Card recurrences table
+CREATE TABLE card_recurrences (
+    card_id CHAR(36) PRIMARY KEY,
+    rule VARCHAR(255) NOT NULL,
+    list_id CHAR(36) NOT NULL,
+    start_at DATETIME NOT NULL,
+    due_after_seconds INT NOT NULL DEFAULT 0,
+    next_at DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (next_at)
+);
+
+-- Automation rules table
+CREATE TABLE rules (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    board_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    trigger_type VARCHAR(32) NOT NULL,
+    trigger_list_id CHAR(36) NOT NULL DEFAULT '',
+    action_type VARCHAR(32) NOT NULL,
+    action_value TEXT NOT NULL,
+    enabled TINYINT(1) NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (board_id, trigger_type)
+);
+
+-- Due date rule runs table
+CREATE TABLE due_rule_runs (
+    card_id CHAR(36) NOT NULL,
+    due_at DATETIME NOT NULL,
+    run_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (card_id, due_at)
+);
+
+-- Notifications table
+CREATE TABLE notifications (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    actor_id CHAR(36) NOT NULL,
+    board_id CHAR(36) NOT NULL,
+    card_id CHAR(36) NOT NULL,
+    comment_id CHAR(36) NOT NULL DEFAULT '',
+    read_at DATETIME NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (user_id, read_at)
+);
+
+-- Reactions table
+CREATE TABLE reactions (
+    target_type VARCHAR(16) NOT NULL,
+    target_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    emoji VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (target_type, target_id, user_id, emoji)
+);
+
+-- Card assignees table
+CREATE TABLE card_assignees (
+    card_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (card_id, user_id)
+);
+
+-- Attachments table
+CREATE TABLE attachments (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    card_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Card links table
+CREATE TABLE card_links (
+    id CHAR(36) DEFAULT UUID() PRIMARY KEY,
+    card_id CHAR(36) NOT NULL,
+    linked_card_id CHAR(36) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY (card_id, linked_card_id, type)
+);
@@ -8,20 +8,22 @@ INSERT INTO boards (id, user_id, title) VALUES
 ('00000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000000', 'Project Beta'),
 ('00000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000000', 'Project Gamma');
 
-INSERT INTO lists (id, board_id, title, position) VALUES 
-('00000000-0000-0000-0000-000000000010','00000000-0000-0000-0000-000000000001', 'To Do', 1),
-('00000000-0000-0000-0000-000000000020','00000000-0000-0000-0000-000000000001', 'In Progress', 2),
-('00000000-0000-0000-0000-000000000030','00000000-0000-0000-0000-000000000001', 'Done', 3),
-('00000000-0000-0000-0000-000000000040','00000000-0000-0000-0000-000000000002', 'Backlog', 1),
-('00000000-0000-0000-0000-000000000050','00000000-0000-0000-0000-000000000002', 'Sprint', 2),
-('00000000-0000-0000-0000-000000000060','00000000-0000-0000-0000-000000000003', 'Ideas', 1);
+INSERT INTO lists (id, board_id, title, rank_key) VALUES 
+('00000000-0000-0000-0000-000000000010','00000000-0000-0000-0000-000000000001', 'To Do', '1'),
+('00000000-0000-0000-0000-000000000020','00000000-0000-0000-0000-000000000001', 'In Progress', '2'),
+('00000000-0000-0000-0000-000000000030','00000000-0000-0000-0000-000000000001', 'Done', '3'),
+('00000000-0000-0000-0000-000000000040','00000000-0000-0000-0000-000000000002', 'Backlog', '1'),
+('00000000-0000-0000-0000-000000000050','00000000-0000-0000-0000-000000000002', 'Sprint', '2'),
+('00000000-0000-0000-0000-000000000060','00000000-0000-0000-0000-000000000003', 'Ideas', '1');
 
-INSERT INTO cards (id, list_id, title, description, position) VALUES 
-('00000000-0000-0000-0000-000000000100', '00000000-0000-0000-0000-000000000010', 'Task 1', 'Description for task 1', 1),
-('00000000-0000-0000-0000-000000000200', '00000000-0000-0000-0000-000000000010', 'Task 2', 'Description for task 2', 2),
-('00000000-0000-0000-0000-000000000300', '00000000-0000-0000-0000-000000000020', 'Task 3', 'Description for task 3', 1),
-('00000000-0000-0000-0000-000000000400', '00000000-0000-0000-0000-000000000030', 'Task 4', 'Description for task 4', 1);
+INSERT INTO cards (id, list_id, title, description, rank_key) VALUES 
+('00000000-0000-0000-0000-000000000100', '00000000-0000-0000-0000-000000000010', 'Task 1', 'Description for task 1', '1'),
+('00000000-0000-0000-0000-000000000200', '00000000-0000-0000-0000-000000000010', 'Task 2', 'Description for task 2', '2'),
+('00000000-0000-0000-0000-000000000300', '00000000-0000-0000-0000-000000000020', 'Task 3', 'Description for task 3', '1'),
+('00000000-0000-0000-0000-000000000400', '00000000-0000-0000-0000-000000000030', 'Task 4', 'Description for task 4', '1');
 
 INSERT INTO comments (id, card_id, user_id, content) VALUES 
 ('00000000-0000-0000-0000-000000001000', '00000000-0000-0000-0000-000000000100', '00000000-0000-0000-0000-000000000000', 'This is a comment on Task 1'),
 ('00000000-0000-0000-0000-000000002000', '00000000-0000-0000-0000-000000000200', '00000000-0000-0000-0000-000000000000', 'This is a comment on Task 2');
+
+INSERT INTO board_members (board_id, user_id, role) SELECT id, user_id, 'owner' FROM boards;
@@ -0,0 +1,27 @@
+USE trellode;
+
+-- Upgrade of a database created when lists, cards and checklist items were ordered by position:
+-- rank_key replaces position, its ranks follow the positions (then the creation dates) among siblings.
+-- Ranks are the row number times 1000 written with 6 base 36 digits, without trailing zeros as rank.Sequence does.
+-- To be run once, position is dropped at the end.
+
+ALTER TABLE lists ADD COLUMN rank_key VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' AFTER position;
+UPDATE lists JOIN (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY board_id ORDER BY position ASC, created_at ASC) AS row_num FROM lists
+) ordered ON ordered.id = lists.id
+SET lists.rank_key = TRIM(TRAILING '0' FROM LPAD(LOWER(CONV(ordered.row_num * 1000, 10, 36)), 6, '0'));
+ALTER TABLE lists DROP COLUMN position;
+
+ALTER TABLE cards ADD COLUMN rank_key VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' AFTER position;
+UPDATE cards JOIN (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY position ASC, created_at ASC) AS row_num FROM cards
+) ordered ON ordered.id = cards.id
+SET cards.rank_key = TRIM(TRAILING '0' FROM LPAD(LOWER(CONV(ordered.row_num * 1000, 10, 36)), 6, '0'));
+ALTER TABLE cards DROP COLUMN position;
+
+ALTER TABLE checklistitems ADD COLUMN rank_key VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' AFTER position;
+UPDATE checklistitems JOIN (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY checklist_id ORDER BY position ASC, created_at ASC) AS row_num FROM checklistitems
+) ordered ON ordered.id = checklistitems.id
+SET checklistitems.rank_key = TRIM(TRAILING '0' FROM LPAD(LOWER(CONV(ordered.row_num * 1000, 10, 36)), 6, '0'));
+ALTER TABLE checklistitems DROP COLUMN position;
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/logging"
+	"trellode-go/internal/utils/messages"
+
+	toolbox_api "github.com/epfl-si/go-toolbox/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) getAttachment(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+
+	attachment, data, severity, err := s.attachmentService.GetAttachmentData(context, id)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetAttachmentFailure"), err.Error(), "", nil))
+		return
+	}
+	defer data.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, data, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}),
+	})
+}
+
+func (s *server) createAttachment(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	// leave some room for the multipart envelope, the exact size is checked on the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, s.attachmentService.MaxSize()+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			c.JSON(http.StatusRequestEntityTooLarge, toolbox_api.MakeError(c, "", http.StatusRequestEntityTooLarge, messages.GetMessage(context.Lang, "AttachmentTooLarge"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidMultipart"), err.Error(), "", nil))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusInternalServerError, toolbox_api.MakeError(c, "", http.StatusInternalServerError, messages.GetMessage(context.Lang, "CreateAttachmentFailure"), err.Error(), "", nil))
+		return
+	}
+	defer file.Close()
+
+	// trust the type sent by the client, then the file extension, then the content itself
+	contentType := fileHeader.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = mime.TypeByExtension(filepath.Ext(fileHeader.Filename))
+	}
+	if contentType == "" {
+		buffer := make([]byte, 512)
+		n, _ := file.Read(buffer)
+		contentType = http.DetectContentType(buffer[:n])
+		_, err = file.Seek(0, 0)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(http.StatusInternalServerError, toolbox_api.MakeError(c, "", http.StatusInternalServerError, messages.GetMessage(context.Lang, "CreateAttachmentFailure"), err.Error(), "", nil))
+			return
+		}
+	}
+
+	attachment := models.Attachment{
+		CardID:      c.Param("id"),
+		Name:        filepath.Base(fileHeader.Filename),
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+	}
+	_, severity, err := s.attachmentService.CreateAttachment(context, &attachment, file)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CreateAttachmentFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(severity, attachment)
+}
+
+func (s *server) deleteAttachment(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+
+	severity, err := s.attachmentService.DeleteAttachment(context, id)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "DeleteAttachmentFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
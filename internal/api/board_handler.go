@@ -19,8 +19,12 @@ func (s *server) getBoard(c *gin.Context) {
 	}
 
 	id := c.Param("id")
+	filter := models.CardFilter{
+		LabelIDs: c.QueryArray("label"),
+		Due:      c.Query("due"),
+	}
 
-	board, severity, err := s.boardService.GetBoard(context, id)
+	board, severity, err := s.boardService.GetBoard(context, id, filter)
 	if err != nil {
 		logging.LogError(s.Log, c, err.Error())
 		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetBoardFailure"), err.Error(), "", nil))
@@ -43,7 +47,7 @@ func (s *server) getBoards(c *gin.Context) {
 		archived = true
 	}
 
-	boards, severity, err := s.boardService.GetBoards(context, archived)
+	boards, severity, err := s.boardService.GetBoards(context, archived, c.Query("workspace"))
 	if err != nil {
 		logging.LogError(s.Log, c, err.Error())
 		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetBoardsFailure"), err.Error(), "", nil))
@@ -88,6 +92,7 @@ func (s *server) updateBoard(c *gin.Context) {
 	if err := c.BindJSON(&board); err == nil {
 		if id != board.ID {
 			c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "IdNotMatching"), "", "", nil))
+			return
 		}
 		severity, err := s.boardService.UpdateBoard(context, id, &board)
 		if err != nil {
@@ -147,3 +152,58 @@ func (s *server) updateListsOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
 	}
 }
+
+type WorkspaceBody struct {
+	WorkspaceID string `json:"workspaceId"`
+}
+
+func (s *server) setBoardWorkspace(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+	var body WorkspaceBody
+	if err := c.BindJSON(&body); err == nil {
+		severity, err := s.boardService.SetWorkspace(context, id, body.WorkspaceID)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateBoardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, nil)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+type CopyBody struct {
+	Title        string `json:"title"`
+	WithComments bool   `json:"withComments"`
+}
+
+func (s *server) copyBoard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+	var body CopyBody
+	if err := c.BindJSON(&body); err == nil {
+		board, severity, err := s.boardService.CopyBoard(context, id, body.Title, body.WithComments)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CopyBoardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, board)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
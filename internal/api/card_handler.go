@@ -39,12 +39,13 @@ func (s *server) createCard(c *gin.Context) {
 
 	var card models.Card
 	if err := c.BindJSON(&card); err == nil {
-		list, severity, err := s.cardService.CreateCard(context, &card)
+		list, warning, severity, err := s.cardService.CreateCard(context, &card)
 		if err != nil {
 			logging.LogError(s.Log, c, err.Error())
 			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CreateCardFailure"), err.Error(), "", nil))
 			return
 		}
+		setWarning(c, warning)
 		c.JSON(severity, list)
 	} else {
 		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
@@ -64,13 +65,15 @@ func (s *server) updateCard(c *gin.Context) {
 	if err := c.BindJSON(&card); err == nil {
 		if id != card.ID {
 			c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "IdNotMatching"), "", "", nil))
+			return
 		}
-		severity, err := s.cardService.UpdateCard(context, &card)
+		warning, severity, err := s.cardService.UpdateCard(context, &card)
 		if err != nil {
 			logging.LogError(s.Log, c, err.Error())
 			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
 			return
 		}
+		setWarning(c, warning)
 		c.JSON(severity, nil)
 	} else {
 		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
@@ -96,3 +99,229 @@ func (s *server) deleteCard(c *gin.Context) {
 
 	c.JSON(severity, nil)
 }
+
+type CopyCardBody struct {
+	CopyBody
+	ListID string `json:"listId"` // target list, defaults to the list of the copied card
+}
+
+func (s *server) copyCard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+	var body CopyCardBody
+	if err := c.BindJSON(&body); err == nil {
+		card, warning, severity, err := s.cardService.CopyCard(context, id, body.ListID, body.Title, body.WithComments)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CopyCardFailure"), err.Error(), "", nil))
+			return
+		}
+		setWarning(c, warning)
+		c.JSON(severity, card)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) assignCard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	cardId := c.Param("id")
+	userId := c.Param("userid")
+
+	severity, err := s.cardService.AssignUser(context, cardId, userId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
+
+func (s *server) unassignCard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	cardId := c.Param("id")
+	userId := c.Param("userid")
+
+	severity, err := s.cardService.UnassignUser(context, cardId, userId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
+
+func (s *server) setCardCover(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var cover models.CardCover
+	if err := c.BindJSON(&cover); err == nil {
+		if cover.Color == "" && (cover.AttachmentID == nil || *cover.AttachmentID == "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "color or attachmentId is required"})
+			return
+		}
+		severity, err := s.cardService.SetCover(context, c.Param("id"), &cover)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, cover)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) removeCardCover(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	severity, err := s.cardService.SetCover(context, c.Param("id"), &models.CardCover{})
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
+
+func (s *server) setCardRecurrence(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var recurrence models.CardRecurrence
+	if err := c.BindJSON(&recurrence); err == nil {
+		if recurrence.Rule == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rule is required"})
+			return
+		}
+		severity, err := s.cardService.SetRecurrence(context, c.Param("id"), &recurrence)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, recurrence)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) removeCardRecurrence(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	severity, err := s.cardService.SetRecurrence(context, c.Param("id"), &models.CardRecurrence{})
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
+
+func (s *server) toggleCardReaction(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var body ReactionBody
+	if err := c.BindJSON(&body); err == nil {
+		reactions, severity, err := s.cardService.ToggleReaction(context, c.Param("id"), body.Emoji)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, reactions)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) addCardLink(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var link models.CardLink
+	if err := c.BindJSON(&link); err == nil {
+		if link.LinkedCardID == "" || link.Type == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "linkedCardId and type are required"})
+			return
+		}
+		link.CardID = c.Param("id")
+		linkId, severity, err := s.cardService.AddLink(context, &link)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, linkId)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) removeCardLink(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	severity, err := s.cardService.RemoveLink(context, c.Param("id"), c.Param("linkid"))
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
@@ -64,6 +64,7 @@ func (s *server) updateChecklist(c *gin.Context) {
 	if err := c.BindJSON(&checklist); err == nil {
 		if id != checklist.ID {
 			c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "IdNotMatching"), "", "", nil))
+			return
 		}
 		severity, err := s.checklistService.UpdateChecklist(context, &checklist)
 		if err != nil {
@@ -151,6 +152,7 @@ func (s *server) updateChecklistItem(c *gin.Context) {
 	if err := c.BindJSON(&checklistItem); err == nil {
 		if id != checklistItem.ID {
 			c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "IdNotMatching"), "", "", nil))
+			return
 		}
 		severity, err := s.checklistService.UpdateChecklistItem(context, &checklistItem)
 		if err != nil {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 	"trellode-go/internal/models"
 	"trellode-go/internal/utils/logging"
 	"trellode-go/internal/utils/messages"
@@ -37,15 +38,24 @@ func (s *server) getComments(c *gin.Context) {
 		return
 	}
 
-	listId := c.Param("id")
+	cardId := c.Param("id")
+	pageSize := 0
+	if c.Query("pagesize") != "" {
+		pageSize, err = strconv.Atoi(c.Query("pagesize"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pagesize"})
+			return
+		}
+	}
 
-	lists, severity, err := s.commentService.GetComments(context, listId)
+	comments, nextCursor, severity, err := s.commentService.GetComments(context, cardId, pageSize, c.Query("cursor"))
 	if err != nil {
 		logging.LogError(s.Log, c, err.Error())
 		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetCommentsFailure"), err.Error(), "", nil))
 		return
 	}
-	c.JSON(http.StatusOK, lists)
+	setNextCursor(c, nextCursor)
+	c.JSON(http.StatusOK, comments)
 }
 
 func (s *server) createComment(c *gin.Context) {
@@ -83,6 +93,7 @@ func (s *server) updateComment(c *gin.Context) {
 	if err := c.BindJSON(&comment); err == nil {
 		if id != comment.ID {
 			c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "IdNotMatching"), "", "", nil))
+			return
 		}
 		severity, err := s.commentService.UpdateComment(context, &comment)
 		if err != nil {
@@ -115,3 +126,46 @@ func (s *server) deleteComment(c *gin.Context) {
 
 	c.JSON(severity, nil)
 }
+
+func (s *server) getCommentRevisions(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	revisions, severity, err := s.commentService.GetRevisions(context, c.Param("id"))
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetCommentFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+type ReactionBody struct {
+	Emoji string `json:"emoji"`
+}
+
+func (s *server) toggleCommentReaction(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var body ReactionBody
+	if err := c.BindJSON(&body); err == nil {
+		reactions, severity, err := s.commentService.ToggleReaction(context, c.Param("id"), body.Emoji)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCommentFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, reactions)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
@@ -28,3 +28,17 @@ func getContext(c *gin.Context) (models.Context, error) {
 		Lang:     langStr,
 	}, nil
 }
+
+// setWarning passes the warning of a successful operation (e.g. a WIP limit exceeded) in the X-Warning header
+func setWarning(c *gin.Context, warning string) {
+	if warning != "" {
+		c.Header("X-Warning", warning)
+	}
+}
+
+// setNextCursor passes the cursor of the next page of a paginated result in the X-Next-Cursor header, none after the last page
+func setNextCursor(c *gin.Context, cursor string) {
+	if cursor != "" {
+		c.Header("X-Next-Cursor", cursor)
+	}
+}
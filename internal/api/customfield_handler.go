@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/logging"
+	"trellode-go/internal/utils/messages"
+
+	toolbox_api "github.com/epfl-si/go-toolbox/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) getCustomFields(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	boardId := c.Param("id")
+
+	fields, severity, err := s.boardService.GetCustomFields(context, boardId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetCustomFieldsFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(http.StatusOK, fields)
+}
+
+func (s *server) createCustomField(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var field models.CustomField
+	if err := c.BindJSON(&field); err == nil {
+		if field.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+		field.BoardID = c.Param("id")
+		fieldId, severity, err := s.boardService.CreateCustomField(context, &field)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CreateCustomFieldFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, fieldId)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) updateCustomField(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var field models.CustomField
+	if err := c.BindJSON(&field); err == nil {
+		if field.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+		field.ID = c.Param("id")
+		severity, err := s.boardService.UpdateCustomField(context, &field)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCustomFieldFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, field)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) deleteCustomField(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+
+	severity, err := s.boardService.DeleteCustomField(context, id)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "DeleteCustomFieldFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/logging"
+	"trellode-go/internal/utils/messages"
+
+	toolbox_api "github.com/epfl-si/go-toolbox/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) exportBoard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+
+	// flat formats for reports, JSON document otherwise
+	format := c.Query("format")
+	if format != "" && format != "json" {
+		data, contentType, severity, err := s.exportService.ExportBoardAs(context, id, format)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "ExportBoardFailure"), err.Error(), "", nil))
+			return
+		}
+		extension := "csv"
+		if format == "markdown" {
+			extension = "md"
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"board-%s.%s\"", id, extension))
+		c.Data(http.StatusOK, contentType, data)
+		return
+	}
+
+	export, severity, err := s.exportService.ExportBoard(context, id)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "ExportBoardFailure"), err.Error(), "", nil))
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"board-%s.json\"", id))
+	c.JSON(http.StatusOK, export)
+}
+
+func (s *server) importBoard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var export models.BoardExport
+	if err := c.BindJSON(&export); err == nil {
+		board, severity, err := s.exportService.ImportBoard(context, &export)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "ImportBoardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, board)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) importTrelloBoard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var trelloBoard models.TrelloBoard
+	if err := c.BindJSON(&trelloBoard); err == nil {
+		report, severity, err := s.trelloService.ImportBoard(context, &trelloBoard)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "ImportTrelloBoardFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, report)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
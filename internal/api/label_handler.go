@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/logging"
+	"trellode-go/internal/utils/messages"
+
+	toolbox_api "github.com/epfl-si/go-toolbox/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) getLabels(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	boardId := c.Param("id")
+
+	labels, severity, err := s.boardService.GetLabels(context, boardId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetLabelsFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(http.StatusOK, labels)
+}
+
+func (s *server) createLabel(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var label models.Label
+	if err := c.BindJSON(&label); err == nil {
+		if label.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+		label.BoardID = c.Param("id")
+		labelId, severity, err := s.boardService.CreateLabel(context, &label)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CreateLabelFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, labelId)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) updateLabel(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var label models.Label
+	if err := c.BindJSON(&label); err == nil {
+		if label.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+		label.ID = c.Param("id")
+		severity, err := s.boardService.UpdateLabel(context, &label)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateLabelFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, label)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) deleteLabel(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+
+	severity, err := s.boardService.DeleteLabel(context, id)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "DeleteLabelFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
+
+func (s *server) addCardLabel(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	cardId := c.Param("id")
+	labelId := c.Param("labelid")
+
+	severity, err := s.cardService.AddLabel(context, cardId, labelId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
+
+func (s *server) removeCardLabel(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	cardId := c.Param("id")
+	labelId := c.Param("labelid")
+
+	severity, err := s.cardService.RemoveLabel(context, cardId, labelId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateCardFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
@@ -64,6 +64,7 @@ func (s *server) updateList(c *gin.Context) {
 	if err := c.BindJSON(&list); err == nil {
 		if id != list.ID {
 			c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "IdNotMatching"), "", "", nil))
+			return
 		}
 		severity, err := s.listService.UpdateList(context, &list)
 		if err != nil {
@@ -109,6 +110,7 @@ func (s *server) updateCardsOrder(c *gin.Context) {
 }
 
 type MoveCardToListBody struct {
+	SourceBoardId   string `json:"sourceboardid"` // board of the source list, defaults to the board of the target list
 	SourceListIndex int    `json:"sourcelistindex"`
 	SourceCardIndex int    `json:"sourcecardindex"`
 	TargetListId    string `json:"targetlistid"`
@@ -129,12 +131,78 @@ func (s *server) moveCardToList(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "all fields are required"})
 			return
 		}
-		severity, err := s.listService.MoveCardToList(context, body.SourceListIndex, body.SourceCardIndex, body.TargetListId, body.TargetCardIndex)
+		warning, severity, err := s.listService.MoveCardToList(context, body.SourceBoardId, body.SourceListIndex, body.SourceCardIndex, body.TargetListId, body.TargetCardIndex)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "MoveCardToListFailure"), err.Error(), "", nil))
+			return
+		}
+		setWarning(c, warning)
+		c.JSON(severity, nil)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+type MoveCardBody struct {
+	FromListID string `json:"fromListId"` // list the card is expected to be in
+	Version    int    `json:"version"`    // version of the card the move is based on
+	ToListID   string `json:"toListId"`
+	Position   int    `json:"position"` // at the end of the list when 0
+}
+
+func (s *server) moveCard(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var body MoveCardBody
+	if err := c.BindJSON(&body); err == nil {
+		if body.FromListID == "" || body.ToListID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "fromListId and toListId are required"})
+			return
+		}
+		warning, severity, err := s.listService.MoveCard(context, c.Param("id"), body.FromListID, body.Version, body.ToListID, body.Position)
 		if err != nil {
 			logging.LogError(s.Log, c, err.Error())
 			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "MoveCardToListFailure"), err.Error(), "", nil))
 			return
 		}
+		setWarning(c, warning)
+		c.JSON(severity, nil)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+type MoveListBody struct {
+	BoardID  string `json:"boardId"`
+	Position int    `json:"position"` // at the end of the board when 0
+}
+
+func (s *server) moveList(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var body MoveListBody
+	if err := c.BindJSON(&body); err == nil {
+		if body.BoardID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "boardId is required"})
+			return
+		}
+		severity, err := s.listService.MoveList(context, c.Param("id"), body.BoardID, body.Position)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateListFailure"), err.Error(), "", nil))
+			return
+		}
 		c.JSON(severity, nil)
 	} else {
 		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
@@ -160,3 +228,31 @@ func (s *server) deleteList(c *gin.Context) {
 
 	c.JSON(severity, nil)
 }
+
+type CopyListBody struct {
+	CopyBody
+	BoardID string `json:"boardId"` // target board, defaults to the board of the copied list
+}
+
+func (s *server) copyList(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+	var body CopyListBody
+	if err := c.BindJSON(&body); err == nil {
+		list, severity, err := s.listService.CopyList(context, id, body.BoardID, body.Title, body.WithComments)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CopyListFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, list)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/logging"
+	"trellode-go/internal/utils/messages"
+
+	toolbox_api "github.com/epfl-si/go-toolbox/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) getMembers(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	boardId := c.Param("id")
+
+	members, severity, err := s.memberService.GetMembers(context, boardId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetMembersFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+func (s *server) createMember(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var member models.BoardMember
+	if err := c.BindJSON(&member); err == nil {
+		if member.UserID == "" && member.Email == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "userId or email is required"})
+			return
+		}
+		member.BoardID = c.Param("id")
+		severity, err := s.memberService.CreateMember(context, &member)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CreateMemberFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, member)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) updateMember(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	var member models.BoardMember
+	if err := c.BindJSON(&member); err == nil {
+		member.BoardID = c.Param("id")
+		member.UserID = c.Param("userid")
+		severity, err := s.memberService.UpdateMember(context, &member)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateMemberFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, nil)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
+
+func (s *server) deleteMember(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	boardId := c.Param("id")
+	userId := c.Param("userid")
+
+	severity, err := s.memberService.DeleteMember(context, boardId, userId)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "DeleteMemberFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"trellode-go/internal/utils/logging"
+	"trellode-go/internal/utils/messages"
+
+	toolbox_api "github.com/epfl-si/go-toolbox/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) getNotifications(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	unreadOnly := c.Query("unread") == "1"
+	pageSize, pageIndex := 0, 0
+	if c.Query("pagesize") != "" {
+		pageSize, err = strconv.Atoi(c.Query("pagesize"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pagesize"})
+			return
+		}
+	}
+	if c.Query("pageindex") != "" {
+		pageIndex, err = strconv.Atoi(c.Query("pageindex"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pageindex"})
+			return
+		}
+	}
+
+	notifications, severity, err := s.notificationService.GetNotifications(context, unreadOnly, pageSize, pageIndex)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetNotificationsFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (s *server) getUnreadNotificationCount(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	count, severity, err := s.notificationService.GetUnreadCount(context)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetNotificationsFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+func (s *server) markNotificationRead(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	severity, err := s.notificationService.MarkRead(context, c.Param("id"))
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateNotificationFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(severity, nil)
+}
+
+func (s *server) markAllNotificationsRead(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	severity, err := s.notificationService.MarkAllRead(context)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "UpdateNotificationFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(severity, nil)
+}
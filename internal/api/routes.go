@@ -16,6 +16,47 @@ func (s *server) Routes() {
 	v1.PUT("/boards/:id", s.updateBoard)
 	v1.DELETE("/boards/:id", s.deleteBoard)
 	v1.PUT("/boards/:id/order", s.updateListsOrder)
+	v1.POST("/boards/:id/copy", s.copyBoard)
+	v1.GET("/boards/:id/export", s.exportBoard)
+	v1.POST("/boards/import", s.importBoard)
+	v1.POST("/boards/import/trello", s.importTrelloBoard)
+
+	v1.GET("/boards/:id/members", s.getMembers)
+	v1.POST("/boards/:id/members", s.createMember)
+	v1.PUT("/boards/:id/members/:userid", s.updateMember)
+	v1.DELETE("/boards/:id/members/:userid", s.deleteMember)
+
+	v1.PUT("/boards/:id/workspace", s.setBoardWorkspace)
+
+	v1.GET("/boards/:id/labels", s.getLabels)
+	v1.POST("/boards/:id/labels", s.createLabel)
+	v1.PUT("/labels/:id", s.updateLabel)
+	v1.DELETE("/labels/:id", s.deleteLabel)
+	v1.PUT("/cards/:id/labels/:labelid", s.addCardLabel)
+	v1.DELETE("/cards/:id/labels/:labelid", s.removeCardLabel)
+	v1.GET("/boards/:id/customfields", s.getCustomFields)
+	v1.POST("/boards/:id/customfields", s.createCustomField)
+	v1.PUT("/customfields/:id", s.updateCustomField)
+	v1.DELETE("/customfields/:id", s.deleteCustomField)
+	v1.GET("/boards/:id/rules", s.getRules)
+	v1.POST("/boards/:id/rules", s.createRule)
+	v1.PUT("/rules/:id", s.updateRule)
+	v1.DELETE("/rules/:id", s.deleteRule)
+
+	v1.GET("/workspaces", s.getWorkspaces)
+	v1.GET("/workspaces/:id", s.getWorkspace)
+	v1.POST("/workspaces", s.createWorkspace)
+	v1.PUT("/workspaces/:id", s.updateWorkspace)
+	v1.DELETE("/workspaces/:id", s.deleteWorkspace)
+	v1.GET("/workspaces/:id/members", s.getWorkspaceMembers)
+	v1.POST("/workspaces/:id/members", s.createWorkspaceMember)
+	v1.PUT("/workspaces/:id/members/:userid", s.updateWorkspaceMember)
+	v1.DELETE("/workspaces/:id/members/:userid", s.deleteWorkspaceMember)
+
+	v1.GET("/templates", s.getTemplates)
+	v1.PUT("/boards/:id/template", s.markTemplate)
+	v1.DELETE("/boards/:id/template", s.unmarkTemplate)
+	v1.POST("/boards/from-template/:id", s.createBoardFromTemplate)
 
 	v1.GET("/lists/:id", s.getList)
 	v1.POST("/lists", s.createList)
@@ -23,17 +64,35 @@ func (s *server) Routes() {
 	v1.DELETE("/lists/:id", s.deleteList)
 	v1.PUT("/lists/:id/order", s.updateCardsOrder)
 	v1.PUT("/lists/:id/move", s.moveCardToList)
+	v1.PUT("/lists/:id/board", s.moveList)
+	v1.POST("/lists/:id/copy", s.copyList)
 
 	v1.GET("/cards/:id", s.getCard)
 	v1.POST("/cards", s.createCard)
 	v1.PUT("/cards/:id", s.updateCard)
 	v1.DELETE("/cards/:id", s.deleteCard)
+	v1.POST("/cards/:id/copy", s.copyCard)
+	v1.PUT("/cards/:id/move", s.moveCard)
+	v1.PUT("/cards/:id/assignees/:userid", s.assignCard)
+	v1.DELETE("/cards/:id/assignees/:userid", s.unassignCard)
+	v1.POST("/cards/:id/links", s.addCardLink)
+	v1.DELETE("/cards/:id/links/:linkid", s.removeCardLink)
+	v1.PUT("/cards/:id/cover", s.setCardCover)
+	v1.DELETE("/cards/:id/cover", s.removeCardCover)
+	v1.PUT("/cards/:id/recurrence", s.setCardRecurrence)
+	v1.DELETE("/cards/:id/recurrence", s.removeCardRecurrence)
+	v1.POST("/cards/:id/reactions", s.toggleCardReaction)
+	v1.POST("/cards/:id/attachments", s.createAttachment)
+	v1.GET("/attachments/:id", s.getAttachment)
+	v1.DELETE("/attachments/:id", s.deleteAttachment)
 
 	v1.GET("/comments/:id", s.getComment)
 	v1.GET("/cards/:id/comments", s.getComments)
 	v1.POST("/comments", s.createComment)
 	v1.PUT("/comments/:id", s.updateComment)
 	v1.DELETE("/comments/:id", s.deleteComment)
+	v1.POST("/comments/:id/reactions", s.toggleCommentReaction)
+	v1.GET("/comments/:id/revisions", s.getCommentRevisions)
 
 	v1.GET("/backgrounds/:id", s.getBackground)
 	v1.GET("/backgrounds", s.getBackgrounds)
@@ -52,12 +111,49 @@ func (s *server) Routes() {
 
 	v1.GET("/logs", s.getLogs)
 
+	v1.GET("/notifications", s.getNotifications)
+	v1.GET("/notifications/count", s.getUnreadNotificationCount)
+	v1.PUT("/notifications/read", s.markAllNotificationsRead)
+	v1.PUT("/notifications/:id/read", s.markNotificationRead)
+
 	v1.OPTIONS("/users/register", s.options)
 	v1.OPTIONS("/users/authenticate", s.options)
 	v1.OPTIONS("/boards", s.options)
 	v1.OPTIONS("/boards/:id", s.options)
 	v1.OPTIONS("/boards/:id/lists", s.options)
 	v1.OPTIONS("/boards/:id/order", s.options)
+	v1.OPTIONS("/boards/:id/members", s.options)
+	v1.OPTIONS("/boards/:id/members/:userid", s.options)
+	v1.OPTIONS("/boards/:id/workspace", s.options)
+	v1.OPTIONS("/boards/:id/labels", s.options)
+	v1.OPTIONS("/labels/:id", s.options)
+	v1.OPTIONS("/cards/:id/labels/:labelid", s.options)
+	v1.OPTIONS("/boards/:id/customfields", s.options)
+	v1.OPTIONS("/customfields/:id", s.options)
+	v1.OPTIONS("/boards/:id/rules", s.options)
+	v1.OPTIONS("/rules/:id", s.options)
+	v1.OPTIONS("/workspaces", s.options)
+	v1.OPTIONS("/workspaces/:id", s.options)
+	v1.OPTIONS("/workspaces/:id/members", s.options)
+	v1.OPTIONS("/workspaces/:id/members/:userid", s.options)
+	v1.OPTIONS("/boards/:id/template", s.options)
+	v1.OPTIONS("/boards/from-template/:id", s.options)
+	v1.OPTIONS("/templates", s.options)
+	v1.OPTIONS("/boards/:id/copy", s.options)
+	v1.OPTIONS("/boards/:id/export", s.options)
+	v1.OPTIONS("/boards/import", s.options)
+	v1.OPTIONS("/boards/import/trello", s.options)
+	v1.OPTIONS("/lists/:id/copy", s.options)
+	v1.OPTIONS("/cards/:id/copy", s.options)
+	v1.OPTIONS("/cards/:id/move", s.options)
+	v1.OPTIONS("/cards/:id/assignees/:userid", s.options)
+	v1.OPTIONS("/cards/:id/links", s.options)
+	v1.OPTIONS("/cards/:id/links/:linkid", s.options)
+	v1.OPTIONS("/cards/:id/cover", s.options)
+	v1.OPTIONS("/cards/:id/recurrence", s.options)
+	v1.OPTIONS("/cards/:id/reactions", s.options)
+	v1.OPTIONS("/cards/:id/attachments", s.options)
+	v1.OPTIONS("/attachments/:id", s.options)
 	v1.OPTIONS("/lists", s.options)
 	v1.OPTIONS("/lists/:id", s.options)
 	v1.OPTIONS("/lists/:id/cards", s.options)
@@ -66,11 +162,18 @@ func (s *server) Routes() {
 	v1.OPTIONS("/cards/:id/comments", s.options)
 	v1.OPTIONS("/comments", s.options)
 	v1.OPTIONS("/comments/:id", s.options)
+	v1.OPTIONS("/comments/:id/reactions", s.options)
+	v1.OPTIONS("/comments/:id/revisions", s.options)
 	v1.OPTIONS("/backgrounds", s.options)
 	v1.OPTIONS("/backgrounds/:id", s.options)
 	v1.OPTIONS("/logs", s.options)
+	v1.OPTIONS("/notifications", s.options)
+	v1.OPTIONS("/notifications/count", s.options)
+	v1.OPTIONS("/notifications/read", s.options)
+	v1.OPTIONS("/notifications/:id/read", s.options)
 	v1.OPTIONS("/lists/:id/order", s.options)
 	v1.OPTIONS("/lists/:id/move", s.options)
+	v1.OPTIONS("/lists/:id/board", s.options)
 	v1.OPTIONS("/checklists", s.options)
 	v1.OPTIONS("/checklists/:id", s.options)
 	v1.OPTIONS("/checklistitems", s.options)
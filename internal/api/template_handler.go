@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"trellode-go/internal/utils/logging"
+	"trellode-go/internal/utils/messages"
+
+	toolbox_api "github.com/epfl-si/go-toolbox/api"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateBoardFromTemplateBody struct {
+	Title string `json:"title"`
+}
+
+func (s *server) getTemplates(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	templates, severity, err := s.templateService.GetTemplates(context)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "GetTemplatesFailure"), err.Error(), "", nil))
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+func (s *server) markTemplate(c *gin.Context) {
+	s.setTemplate(c, true)
+}
+
+func (s *server) unmarkTemplate(c *gin.Context) {
+	s.setTemplate(c, false)
+}
+
+func (s *server) setTemplate(c *gin.Context, isTemplate bool) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+
+	severity, err := s.templateService.SetTemplate(context, id, isTemplate)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "SetTemplateFailure"), err.Error(), "", nil))
+		return
+	}
+
+	c.JSON(severity, nil)
+}
+
+func (s *server) createBoardFromTemplate(c *gin.Context) {
+	context, err := getContext(c)
+	if err != nil {
+		logging.LogError(s.Log, c, err.Error())
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "GetContextFailure"), err.Error(), "", nil))
+		return
+	}
+
+	id := c.Param("id")
+	var body CreateBoardFromTemplateBody
+	if err := c.BindJSON(&body); err == nil {
+		board, severity, err := s.templateService.CreateBoardFromTemplate(context, id, body.Title)
+		if err != nil {
+			logging.LogError(s.Log, c, err.Error())
+			c.JSON(severity, toolbox_api.MakeError(c, "", severity, messages.GetMessage(context.Lang, "CreateBoardFromTemplateFailure"), err.Error(), "", nil))
+			return
+		}
+		c.JSON(severity, board)
+	} else {
+		c.JSON(http.StatusBadRequest, toolbox_api.MakeError(c, "", http.StatusBadRequest, messages.GetMessage(context.Lang, "InvalidJson"), err.Error(), "", nil))
+	}
+}
@@ -1,18 +1,26 @@
 package api
 
 import (
+	"trellode-go/internal/attachment"
 	"trellode-go/internal/background"
 	"trellode-go/internal/board"
 	"trellode-go/internal/card"
 	"trellode-go/internal/checklist"
 	"trellode-go/internal/comment"
+	"trellode-go/internal/export"
 	"trellode-go/internal/list"
 	internalLog "trellode-go/internal/log"
+	"trellode-go/internal/member"
 	"trellode-go/internal/models"
+	"trellode-go/internal/notification"
+	"trellode-go/internal/template"
+	"trellode-go/internal/trello"
 	"trellode-go/internal/user"
 	"trellode-go/internal/utils/config"
 	"trellode-go/internal/utils/logging"
 	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/storage"
+	"trellode-go/internal/workspace"
 
 	toolbox_api "github.com/epfl-si/go-toolbox/api"
 
@@ -27,36 +35,55 @@ import (
 )
 
 type server struct {
-	db                *gorm.DB
-	i18nBundle        *i18n.Bundle
-	Router            *gin.Engine
-	Log               *zap.Logger
-	userService       user.UserService
-	boardService      board.BoardService
-	listService       list.ListService
-	cardService       card.CardService
-	commentService    comment.CommentService
-	backgroundService background.BackgroundService
-	checklistService  checklist.ChecklistService
-	logService        internalLog.LogService
+	db                  *gorm.DB
+	i18nBundle          *i18n.Bundle
+	Router              *gin.Engine
+	Log                 *zap.Logger
+	userService         user.UserService
+	boardService        board.BoardService
+	listService         list.ListService
+	cardService         card.CardService
+	commentService      comment.CommentService
+	backgroundService   background.BackgroundService
+	checklistService    checklist.ChecklistService
+	logService          internalLog.LogService
+	memberService       member.MemberService
+	templateService     template.TemplateService
+	exportService       export.ExportService
+	trelloService       trello.TrelloService
+	workspaceService    workspace.WorkspaceService
+	attachmentService   attachment.AttachmentService
+	notificationService notification.NotificationService
 }
 
 func NewServer(db *gorm.DB, router *gin.Engine, log *zap.Logger) *server {
+	attachmentStorage, err := storage.NewStorageFromEnv()
+	if err != nil {
+		log.Fatal("Invalid attachment storage configuration: " + err.Error())
+	}
+
 	logService := internalLog.NewLogService(internalLog.NewLogRepository(db, log))
 	userService := user.NewUserService(user.NewUserRepository(db, log))
-	boardService := board.NewBoardService(board.NewBoardRepository(db, log, logService))
-	listService := list.NewListService(list.NewListRepository(db, log, logService))
-	cardService := card.NewCardService(card.NewCardRepository(db, log, logService))
-	commentService := comment.NewCommentService(comment.NewCommentRepository(db, log, logService))
-	checklistService := checklist.NewChecklistService(checklist.NewChecklistRepository(db, log, logService))
+	memberService := member.NewMemberService(member.NewMemberRepository(db, log, logService))
+	workspaceService := workspace.NewWorkspaceService(workspace.NewWorkspaceRepository(db, log, logService))
+	boardService := board.NewBoardService(board.NewBoardRepository(db, log, logService, attachmentStorage), memberService, workspaceService)
+	listService := list.NewListService(list.NewListRepository(db, log, logService, attachmentStorage), memberService)
+	cardService := card.NewCardService(card.NewCardRepository(db, log, logService, attachmentStorage), memberService)
+	commentService := comment.NewCommentService(comment.NewCommentRepository(db, log, logService), memberService)
+	checklistService := checklist.NewChecklistService(checklist.NewChecklistRepository(db, log, logService), memberService)
 	backgroundService := background.NewBackgroundService(background.NewBackgroundRepository(db, log, logService))
+	templateService := template.NewTemplateService(template.NewTemplateRepository(db, log, logService), memberService)
+	exportService := export.NewExportService(export.NewExportRepository(db, log, logService), memberService)
+	trelloService := trello.NewTrelloService(trello.NewTrelloRepository(db, log, logService))
+	attachmentService := attachment.NewAttachmentService(attachment.NewAttachmentRepository(db, log, logService, attachmentStorage, attachment.MaxSizeFromEnv()), memberService)
+	notificationService := notification.NewNotificationService(notification.NewNotificationRepository(db, log))
 
 	// i18n for error messages
 	bundle := i18n.NewBundle(language.French)
 	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
 
 	// load translation files
-	_, err := bundle.LoadMessageFile("i18n/fr.toml")
+	_, err = bundle.LoadMessageFile("i18n/fr.toml")
 	if err != nil {
 		_, err := bundle.LoadMessageFile("../../assets/i18n/fr.toml")
 		if err != nil {
@@ -71,7 +98,7 @@ func NewServer(db *gorm.DB, router *gin.Engine, log *zap.Logger) *server {
 		}
 	}
 
-	return &server{db, bundle, router, log, userService, boardService, listService, cardService, commentService, backgroundService, checklistService, logService}
+	return &server{db, bundle, router, log, userService, boardService, listService, cardService, commentService, backgroundService, checklistService, logService, memberService, templateService, exportService, trelloService, workspaceService, attachmentService, notificationService}
 }
 
 // RegisterUser 	godoc
@@ -0,0 +1,222 @@
+package attachment
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"trellode-go/internal/log"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/storage"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	logService log.LogService
+	storage    storage.Storage
+	maxSize    int64
+}
+
+type AttachmentRepositoryInterface interface {
+	GetAttachment(models.Context, string) (*models.Attachment, int, error)
+	GetAttachmentData(models.Context, string) (*models.Attachment, io.ReadCloser, int, error)
+	CreateAttachment(models.Context, *models.Attachment, io.Reader) (string, int, error)
+	DeleteAttachment(models.Context, string) (int, error)
+	MaxSize() int64
+}
+
+// NewAttachmentRepository returns a repository keeping attachment metadata in db and their content in storage,
+// attachments larger than maxSize bytes are refused
+func NewAttachmentRepository(db *gorm.DB, log *zap.Logger, logService log.LogService, storage storage.Storage, maxSize int64) AttachmentRepository {
+	return AttachmentRepository{
+		db:         db,
+		log:        log,
+		logService: logService,
+		storage:    storage,
+		maxSize:    maxSize,
+	}
+}
+
+func (repo AttachmentRepository) MaxSize() int64 {
+	return repo.maxSize
+}
+
+func (repo AttachmentRepository) GetAttachment(context models.Context, id string) (*models.Attachment, int, error) {
+	var attachment *models.Attachment
+	err := repo.db.Where("id = ?", id).First(&attachment).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if attachment.ID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "AttachmentNotFound"))
+	}
+
+	return attachment, http.StatusOK, nil
+}
+
+// GetAttachmentData returns an attachment with its content, the caller has to close it
+func (repo AttachmentRepository) GetAttachmentData(context models.Context, id string) (*models.Attachment, io.ReadCloser, int, error) {
+	attachment, severity, err := repo.GetAttachment(context, id)
+	if err != nil {
+		return nil, nil, severity, err
+	}
+
+	data, err := repo.storage.Get(attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "AttachmentNotFound"))
+	}
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	return attachment, data, http.StatusOK, nil
+}
+
+// CreateAttachment stores data and records the attachment, data must contain attachment.Size bytes
+func (repo AttachmentRepository) CreateAttachment(context models.Context, attachment *models.Attachment, data io.Reader) (string, int, error) {
+	if attachment.Size > repo.maxSize {
+		return "", http.StatusRequestEntityTooLarge, errors.New(messages.GetMessage(context.Lang, "AttachmentTooLarge"))
+	}
+	var card models.Card
+	err := repo.db.Where("id = ?", attachment.CardID).First(&card).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if card.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
+	}
+	boardId, err := repo.getBoardIdOfCard(&card)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	attachment.ID = uuid.NewString()
+	attachment.UserID = context.UserId
+	attachment.StorageKey = "cards/" + card.ID + "/" + attachment.ID
+
+	err = repo.storage.Put(attachment.StorageKey, io.LimitReader(data, attachment.Size), attachment.Size, attachment.ContentType)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:   "attachment",
+		ToValue: attachment.Name,
+	}})
+	if err != nil {
+		repo.removeData(attachment.StorageKey)
+		return "", http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Create(&attachment).Error
+	if err != nil {
+		tx.Rollback()
+		repo.removeData(attachment.StorageKey)
+		return "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "createattachment",
+		ActionTargetID: attachment.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		repo.removeData(attachment.StorageKey)
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return attachment.ID, http.StatusCreated, nil
+}
+
+func (repo AttachmentRepository) DeleteAttachment(context models.Context, id string) (int, error) {
+	attachment, severity, err := repo.GetAttachment(context, id)
+	if err != nil {
+		return severity, err
+	}
+	var card models.Card
+	err = repo.db.Where("id = ?", attachment.CardID).First(&card).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	boardId, err := repo.getBoardIdOfCard(&card)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// the name is kept in the log since the attachment is gone
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "attachment",
+		FromValue: attachment.Name,
+	}})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Delete(&attachment).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove the cover made of this attachment
+	err = tx.Model(&models.Card{}).
+		Where("cover_attachment_id = ?", attachment.ID).
+		Select("cover_color", "cover_attachment_id", "cover_thumbnail", "cover_text_color").
+		Updates(&models.Card{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "deleteattachment",
+		ActionTargetID: attachment.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	repo.removeData(attachment.StorageKey)
+
+	return http.StatusAccepted, nil
+}
+
+// removeData deletes the content of an attachment, failures only leave an orphan object behind so they are just logged
+func (repo AttachmentRepository) removeData(key string) {
+	err := repo.storage.Delete(key)
+	if err != nil {
+		repo.log.Warn("Failed to delete attachment data " + key + ": " + err.Error())
+	}
+}
+
+func (repo AttachmentRepository) getBoardIdOfCard(card *models.Card) (string, error) {
+	var list models.List
+	err := repo.db.Where("id = ?", card.ListID).First(&list).Error
+	if err != nil {
+		return "", err
+	}
+
+	return list.BoardID, nil
+}
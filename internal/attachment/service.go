@@ -0,0 +1,72 @@
+package attachment
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"trellode-go/internal/member"
+	"trellode-go/internal/models"
+)
+
+// DefaultMaxSize is the maximum size of an attachment when ATTACHMENT_MAX_SIZE is not set: 10 MiB
+const DefaultMaxSize = 10 << 20
+
+type AttachmentServiceInterface interface {
+	GetAttachmentData(models.Context, string) (*models.Attachment, io.ReadCloser, int, error)
+	CreateAttachment(models.Context, *models.Attachment, io.Reader) (string, int, error)
+	DeleteAttachment(models.Context, string) (int, error)
+	MaxSize() int64
+}
+
+type AttachmentService struct {
+	repo          AttachmentRepositoryInterface
+	memberService member.MemberService
+}
+
+// NewAttachmentService returns a service to manipulate card attachments
+func NewAttachmentService(repo AttachmentRepositoryInterface, memberService member.MemberService) AttachmentService {
+	return AttachmentService{
+		repo:          repo,
+		memberService: memberService,
+	}
+}
+
+// MaxSizeFromEnv returns the maximum size of an attachment in bytes, from ATTACHMENT_MAX_SIZE
+func MaxSizeFromEnv() int64 {
+	maxSize, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_SIZE"), 10, 64)
+	if err != nil || maxSize <= 0 {
+		return DefaultMaxSize
+	}
+	return maxSize
+}
+
+func (s AttachmentService) MaxSize() int64 {
+	return s.repo.MaxSize()
+}
+
+func (s AttachmentService) GetAttachmentData(context models.Context, id string) (*models.Attachment, io.ReadCloser, int, error) {
+	severity, err := s.memberService.CheckAttachmentRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, nil, severity, err
+	}
+
+	return s.repo.GetAttachmentData(context, id)
+}
+
+func (s AttachmentService) CreateAttachment(context models.Context, attachment *models.Attachment, data io.Reader) (string, int, error) {
+	severity, err := s.memberService.CheckCardRole(context, attachment.CardID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return s.repo.CreateAttachment(context, attachment, data)
+}
+
+func (s AttachmentService) DeleteAttachment(context models.Context, id string) (int, error) {
+	severity, err := s.memberService.CheckAttachmentRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.DeleteAttachment(context, id)
+}
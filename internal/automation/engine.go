@@ -0,0 +1,429 @@
+package automation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"trellode-go/internal/log"
+	"trellode-go/internal/member/access"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/blocked"
+	"trellode-go/internal/utils/mention"
+	"trellode-go/internal/utils/rank"
+	"trellode-go/internal/utils/wip"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MaxDepth is how many times the actions of rules can trigger other rules in a row
+const MaxDepth = 5
+
+// Event is something that happened to a card, which may trigger the rules of its board
+type Event struct {
+	Trigger string // models.RuleTrigger...
+	BoardID string
+	CardID  string
+	ListID  string // list the card was created in or moved into
+}
+
+type Engine struct {
+	logService log.LogService
+	log        *zap.Logger
+}
+
+func NewEngine(logService log.LogService, log *zap.Logger) Engine {
+	return Engine{
+		logService: logService,
+		log:        log,
+	}
+}
+
+type queuedEvent struct {
+	Event
+	depth int
+}
+
+// Run applies in tx the rules of the board matching the event, then the rules matching the events caused by their actions.
+// Against infinite loops, a rule is applied at most once to a card in a run and chains stop after MaxDepth rules.
+// Operations are logged by models.AutomationUserID, rules whose list or label no longer exists or whose user
+// no longer has a role on the board are skipped.
+func (e Engine) Run(context models.Context, tx *gorm.DB, event Event) error {
+	ruleContext := models.Context{
+		UserId:   models.AutomationUserID,
+		UserType: context.UserType,
+		Lang:     context.Lang,
+	}
+
+	return e.run(event,
+		func(event Event) ([]*models.Rule, error) {
+			return matchingRules(tx, event)
+		},
+		func(rule *models.Rule, event Event) (bool, error) {
+			return e.apply(ruleContext, tx, rule, event)
+		})
+}
+
+// run is the loop of Run: match returns the rules matching an event in order, apply applies a rule
+// to the card of an event and tells if it changed the card
+func (e Engine) run(event Event, match func(Event) ([]*models.Rule, error), apply func(*models.Rule, Event) (bool, error)) error {
+	applied := map[string]bool{}
+	queue := []queuedEvent{{Event: event}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		rules, err := match(current.Event)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			key := rule.ID + "/" + current.CardID
+			if applied[key] {
+				continue
+			}
+			if current.depth >= MaxDepth {
+				e.log.Warn("Rule " + rule.ID + " not applied to card " + current.CardID + ": too many rules in a row")
+				continue
+			}
+			applied[key] = true
+
+			changed, err := apply(rule, current.Event)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+			for _, next := range causedEvents(rule, current.Event) {
+				queue = append(queue, queuedEvent{Event: next, depth: current.depth + 1})
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchingRules(tx *gorm.DB, event Event) ([]*models.Rule, error) {
+	rules := []*models.Rule{}
+	err := tx.
+		Where("board_id = ? AND trigger_type = ? AND enabled = ?", event.BoardID, event.Trigger, true).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []*models.Rule{}
+	for _, rule := range rules {
+		if matches(rule, event) {
+			matching = append(matching, rule)
+		}
+	}
+
+	return matching, nil
+}
+
+// matches tells if an event triggers a rule
+func matches(rule *models.Rule, event Event) bool {
+	if !rule.Enabled || rule.BoardID != event.BoardID || rule.Trigger != event.Trigger {
+		return false
+	}
+	if event.Trigger == models.RuleTriggerCardCreated || event.Trigger == models.RuleTriggerCardMoved {
+		return rule.TriggerListID == "" || rule.TriggerListID == event.ListID
+	}
+
+	return true
+}
+
+// causedEvents returns the events caused by the action of a rule once it changed the card of the event
+func causedEvents(rule *models.Rule, event Event) []Event {
+	switch rule.Action {
+	case models.RuleActionMoveCard:
+		return []Event{{
+			Trigger: models.RuleTriggerCardMoved,
+			BoardID: event.BoardID,
+			CardID:  event.CardID,
+			ListID:  rule.ActionValue,
+		}}
+	case models.RuleActionCheckAll:
+		// the checklists that had unchecked items are now completed
+		return []Event{{
+			Trigger: models.RuleTriggerChecklistCompleted,
+			BoardID: event.BoardID,
+			CardID:  event.CardID,
+		}}
+	}
+
+	return nil
+}
+
+// apply applies the action of a rule to the card of the event and tells if it changed the card
+func (e Engine) apply(context models.Context, tx *gorm.DB, rule *models.Rule, event Event) (bool, error) {
+	var card models.Card
+	err := tx.Where("id = ?", event.CardID).First(&card).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if card.ID == "" {
+		return false, nil
+	}
+
+	switch rule.Action {
+	case models.RuleActionMoveCard:
+		return e.moveCard(context, tx, rule, event, &card)
+	case models.RuleActionAddLabel:
+		return e.addLabel(context, tx, rule, event, &card)
+	case models.RuleActionAssign:
+		return e.assign(context, tx, rule, event, &card)
+	case models.RuleActionCheckAll:
+		return e.checkAll(context, tx, rule, event, &card)
+	case models.RuleActionArchive:
+		return e.archive(context, tx, rule, event, &card)
+	case models.RuleActionComment:
+		return e.comment(context, tx, rule, event, &card)
+	}
+
+	return false, nil
+}
+
+func (e Engine) moveCard(context models.Context, tx *gorm.DB, rule *models.Rule, event Event, card *models.Card) (bool, error) {
+	if card.ListID == rule.ActionValue {
+		return false, nil
+	}
+	var lists []models.List
+	err := tx.Where("id IN ? AND board_id = ?", []string{card.ListID, rule.ActionValue}, event.BoardID).Find(&lists).Error
+	if err != nil {
+		return false, err
+	}
+	var sourceList, targetList models.List
+	for _, list := range lists {
+		if list.ID == card.ListID {
+			sourceList = list
+		} else if list.ArchivedAt == nil {
+			targetList = list
+		}
+	}
+	if targetList.ID == "" {
+		e.log.Warn("Rule " + rule.ID + " skipped: list " + rule.ActionValue + " not found on board " + event.BoardID)
+		return false, nil
+	}
+	// the rule is skipped where a manual move would be refused, an exceeded soft WIP limit is only logged
+	severity, err := blocked.Check(context, tx, card, &targetList)
+	warning := ""
+	if err == nil {
+		warning, severity, err = wip.Check(context, tx, &targetList, card.ID)
+	}
+	if severity == http.StatusConflict {
+		e.log.Warn("Rule " + rule.ID + " skipped: " + err.Error())
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// the card goes at the end of the target list
+	var lastRank *string
+	err = tx.Model(&models.Card{}).Where("list_id = ?", targetList.ID).Select("MAX(rank_key)").Row().Scan(&lastRank)
+	if err != nil {
+		return false, err
+	}
+	last := ""
+	if lastRank != nil {
+		last = *lastRank
+	}
+	err = tx.Model(&models.Card{}).
+		Where("id = ?", card.ID).
+		Updates(map[string]interface{}{"list_id": targetList.ID, "rank_key": rank.Between(last, ""), "version": gorm.Expr("version + 1")}).Error
+	if err != nil {
+		return false, err
+	}
+
+	changes := []*models.LogChange{{
+		Field:     "list",
+		FromValue: sourceList.Title,
+		ToValue:   targetList.Title,
+	}}
+	if warning != "" {
+		changes = append(changes, &models.LogChange{
+			Field:   "wipLimit",
+			ToValue: warning,
+		})
+	}
+	err = e.createLog(context, tx, rule, event.BoardID, "movecard", card.ID, changes...)
+
+	return err == nil, err
+}
+
+func (e Engine) addLabel(context models.Context, tx *gorm.DB, rule *models.Rule, event Event, card *models.Card) (bool, error) {
+	var labels []models.Label
+	err := tx.Where("id IN (SELECT label_id FROM card_labels WHERE card_id = ?)", card.ID).Find(&labels).Error
+	if err != nil {
+		return false, err
+	}
+	labelsBefore := []string{}
+	for _, label := range labels {
+		if label.ID == rule.ActionValue {
+			return false, nil
+		}
+		labelsBefore = append(labelsBefore, label.Name)
+	}
+	sort.Strings(labelsBefore)
+
+	var label models.Label
+	err = tx.Where("id = ? AND board_id = ?", rule.ActionValue, event.BoardID).First(&label).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if label.ID == "" {
+		e.log.Warn("Rule " + rule.ID + " skipped: label " + rule.ActionValue + " not found on board " + event.BoardID)
+		return false, nil
+	}
+
+	err = tx.Create(&models.CardLabel{CardID: card.ID, LabelID: label.ID}).Error
+	if err != nil {
+		return false, err
+	}
+
+	labelsAfter := append([]string{label.Name}, labelsBefore...)
+	sort.Strings(labelsAfter)
+	err = e.createLog(context, tx, rule, event.BoardID, "updatecard", card.ID, &models.LogChange{
+		Field:     "labels",
+		FromValue: strings.Join(labelsBefore, ", "),
+		ToValue:   strings.Join(labelsAfter, ", "),
+	})
+
+	return err == nil, err
+}
+
+func (e Engine) assign(context models.Context, tx *gorm.DB, rule *models.Rule, event Event, card *models.Card) (bool, error) {
+	var count int64
+	err := tx.Model(&models.CardAssignee{}).Where("card_id = ? AND user_id = ?", card.ID, rule.ActionValue).Count(&count).Error
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	// the user may have lost their role on the board since the rule was saved
+	var user models.User
+	err = tx.Where("id = ? AND id IN (?)", rule.ActionValue, access.BoardUsers(tx, event.BoardID)).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if user.ID == "" {
+		e.log.Warn("Rule " + rule.ID + " skipped: user " + rule.ActionValue + " not found on board " + event.BoardID)
+		return false, nil
+	}
+
+	err = tx.Create(&models.CardAssignee{CardID: card.ID, UserID: user.ID}).Error
+	if err != nil {
+		return false, err
+	}
+
+	err = e.createLog(context, tx, rule, event.BoardID, "assigncard", card.ID, &models.LogChange{
+		Field:   "assignee",
+		ToValue: user.Firstname + " " + user.Lastname,
+	})
+
+	return err == nil, err
+}
+
+func (e Engine) checkAll(context models.Context, tx *gorm.DB, rule *models.Rule, event Event, card *models.Card) (bool, error) {
+	var items []models.ChecklistItem
+	err := tx.
+		Where("checked = ? AND checklist_id IN (SELECT id FROM checklists WHERE card_id = ? AND archived_at IS NULL)", false, card.ID).
+		Find(&items).Error
+	if err != nil || len(items) == 0 {
+		return false, err
+	}
+
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	err = tx.Model(&models.ChecklistItem{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"checked": true, "updated_at": time.Now()}).Error
+	if err != nil {
+		return false, err
+	}
+
+	err = e.createLog(context, tx, rule, event.BoardID, "updatecard", card.ID, &models.LogChange{
+		Field:   "checkeditems",
+		ToValue: strconv.Itoa(len(items)),
+	})
+
+	return err == nil, err
+}
+
+func (e Engine) archive(context models.Context, tx *gorm.DB, rule *models.Rule, event Event, card *models.Card) (bool, error) {
+	if card.ArchivedAt != nil {
+		return false, nil
+	}
+
+	err := tx.Model(&models.Card{}).
+		Where("id = ?", card.ID).
+		Updates(map[string]interface{}{"archived_at": time.Now(), "version": gorm.Expr("version + 1")}).Error
+	if err != nil {
+		return false, err
+	}
+
+	err = e.createLog(context, tx, rule, event.BoardID, "archivecard", card.ID)
+
+	return err == nil, err
+}
+
+func (e Engine) comment(context models.Context, tx *gorm.DB, rule *models.Rule, event Event, card *models.Card) (bool, error) {
+	comment := models.Comment{
+		ID:      uuid.NewString(),
+		CardID:  card.ID,
+		UserID:  context.UserId,
+		Content: rule.ActionValue,
+	}
+	err := tx.Create(&comment).Error
+	if err != nil {
+		return false, err
+	}
+
+	err = e.createLog(context, tx, rule, event.BoardID, "createcomment", comment.ID)
+	if err != nil {
+		return false, err
+	}
+
+	// notify mentioned users
+	err = mention.Notify(tx, comment.Content, "", models.Notification{
+		Type:      models.NotificationTypeMention,
+		ActorID:   context.UserId,
+		BoardID:   event.BoardID,
+		CardID:    comment.CardID,
+		CommentID: comment.ID,
+	})
+
+	return err == nil, err
+}
+
+// createLog logs an operation made by a rule, the rule is the first change
+func (e Engine) createLog(context models.Context, tx *gorm.DB, rule *models.Rule, boardId string, action string, targetId string, changes ...*models.LogChange) error {
+	changes = append([]*models.LogChange{{
+		Field:   "rule",
+		ToValue: rule.Name,
+	}}, changes...)
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = e.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         action,
+		ActionTargetID: targetId,
+		Changes:        string(changesJson),
+	})
+
+	return err
+}
@@ -0,0 +1,135 @@
+package automation
+
+import (
+	"strconv"
+	"testing"
+	"trellode-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeBoard keeps the rules and the state of one card in memory, moves and checks change the card
+type fakeBoard struct {
+	rules   []*models.Rule
+	listId  string
+	checked bool
+	applied []string
+}
+
+func (board *fakeBoard) match(event Event) ([]*models.Rule, error) {
+	rules := []*models.Rule{}
+	for _, rule := range board.rules {
+		if matches(rule, event) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (board *fakeBoard) apply(rule *models.Rule, event Event) (bool, error) {
+	board.applied = append(board.applied, rule.ID)
+	switch rule.Action {
+	case models.RuleActionMoveCard:
+		if board.listId == rule.ActionValue {
+			return false, nil
+		}
+		board.listId = rule.ActionValue
+		return true, nil
+	case models.RuleActionCheckAll:
+		if board.checked {
+			return false, nil
+		}
+		board.checked = true
+		return true, nil
+	}
+	return true, nil
+}
+
+func (board *fakeBoard) run(t *testing.T, event Event) {
+	engine := Engine{log: zap.NewNop()}
+	err := engine.run(event, board.match, board.apply)
+	assert.NoError(t, err)
+}
+
+func moveRule(id string, fromListId string, toListId string) *models.Rule {
+	return &models.Rule{
+		ID:            id,
+		BoardID:       "board",
+		Trigger:       models.RuleTriggerCardMoved,
+		TriggerListID: fromListId,
+		Action:        models.RuleActionMoveCard,
+		ActionValue:   toListId,
+		Enabled:       true,
+	}
+}
+
+func movedEvent(listId string) Event {
+	return Event{Trigger: models.RuleTriggerCardMoved, BoardID: "board", CardID: "card", ListID: listId}
+}
+
+func TestRunPingPong(t *testing.T) {
+	// two rules moving the card back and forth apply once each
+	board := &fakeBoard{
+		rules:  []*models.Rule{moveRule("a", "todo", "doing"), moveRule("b", "doing", "todo")},
+		listId: "todo",
+	}
+	board.run(t, movedEvent("todo"))
+
+	assert.Equal(t, []string{"a", "b"}, board.applied)
+	assert.Equal(t, "todo", board.listId)
+}
+
+func TestRunMaxDepth(t *testing.T) {
+	// a chain of moves longer than MaxDepth, each rule triggered by the move of the previous one
+	board := &fakeBoard{listId: "list0"}
+	for i := 0; i < MaxDepth+3; i++ {
+		board.rules = append(board.rules, moveRule("rule"+strconv.Itoa(i), "list"+strconv.Itoa(i), "list"+strconv.Itoa(i+1)))
+	}
+	board.run(t, movedEvent("list0"))
+
+	assert.Len(t, board.applied, MaxDepth)
+	assert.Equal(t, "list"+strconv.Itoa(MaxDepth), board.listId)
+}
+
+func TestRunChaining(t *testing.T) {
+	// moving into done checks everything, which completes the checklists and archives the card
+	board := &fakeBoard{
+		rules: []*models.Rule{
+			moveRule("move", "review", "done"),
+			{ID: "check", BoardID: "board", Trigger: models.RuleTriggerCardMoved, TriggerListID: "done", Action: models.RuleActionCheckAll, Enabled: true},
+			{ID: "archive", BoardID: "board", Trigger: models.RuleTriggerChecklistCompleted, Action: models.RuleActionArchive, Enabled: true},
+			{ID: "disabled", BoardID: "board", Trigger: models.RuleTriggerChecklistCompleted, Action: models.RuleActionComment, ActionValue: "done"},
+			{ID: "other", BoardID: "other", Trigger: models.RuleTriggerChecklistCompleted, Action: models.RuleActionArchive, Enabled: true},
+		},
+		listId: "review",
+	}
+	board.run(t, movedEvent("review"))
+
+	assert.Equal(t, []string{"move", "check", "archive"}, board.applied)
+	assert.True(t, board.checked)
+}
+
+func TestRunUnchangedCard(t *testing.T) {
+	// checking a card whose items are already checked does not complete its checklists again
+	board := &fakeBoard{
+		rules: []*models.Rule{
+			{ID: "check", BoardID: "board", Trigger: models.RuleTriggerCardCreated, Action: models.RuleActionCheckAll, Enabled: true},
+			{ID: "archive", BoardID: "board", Trigger: models.RuleTriggerChecklistCompleted, Action: models.RuleActionArchive, Enabled: true},
+		},
+		checked: true,
+	}
+	board.run(t, Event{Trigger: models.RuleTriggerCardCreated, BoardID: "board", CardID: "card", ListID: "todo"})
+
+	assert.Equal(t, []string{"check"}, board.applied)
+}
+
+func TestMatches(t *testing.T) {
+	rule := moveRule("rule", "done", "archive")
+	assert.True(t, matches(rule, movedEvent("done")))
+	assert.False(t, matches(rule, movedEvent("todo")))
+
+	created := &models.Rule{BoardID: "board", Trigger: models.RuleTriggerCardCreated, Enabled: true}
+	assert.True(t, matches(created, Event{Trigger: models.RuleTriggerCardCreated, BoardID: "board", ListID: "todo"}))
+	assert.False(t, matches(created, Event{Trigger: models.RuleTriggerCardMoved, BoardID: "board", ListID: "todo"}))
+}
@@ -0,0 +1,73 @@
+package automation
+
+import (
+	"time"
+	"trellode-go/internal/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AutomationRepository struct {
+	db     *gorm.DB
+	log    *zap.Logger
+	engine Engine
+}
+
+type AutomationRepositoryInterface interface {
+	GetPassedDueCards(time.Time) ([]*models.DueCard, error)
+	RunDueRules(*models.DueCard, time.Time) error
+}
+
+func NewAutomationRepository(db *gorm.DB, log *zap.Logger, engine Engine) AutomationRepository {
+	return AutomationRepository{
+		db:     db,
+		log:    log,
+		engine: engine,
+	}
+}
+
+// GetPassedDueCards returns the open cards, on open lists and boards, whose due date passed before now
+// while their board had an enabled due date rule, and whose due date rules have not run yet
+func (repo AutomationRepository) GetPassedDueCards(now time.Time) ([]*models.DueCard, error) {
+	cards := []*models.DueCard{}
+	err := repo.db.
+		Table("cards").
+		Distinct("cards.id AS card_id, cards.title, cards.due_at, boards.id AS board_id, boards.title AS board_title").
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Joins("JOIN boards ON boards.id = lists.board_id").
+		Joins("JOIN rules ON rules.board_id = boards.id AND rules.trigger_type = ? AND rules.enabled = ? AND rules.created_at <= cards.due_at", models.RuleTriggerDuePassed, true).
+		Where("cards.archived_at IS NULL AND lists.archived_at IS NULL AND boards.archived_at IS NULL").
+		Where("cards.completed_at IS NULL AND cards.due_at <= ?", now).
+		Where("NOT EXISTS (SELECT 1 FROM due_rule_runs WHERE due_rule_runs.card_id = cards.id AND due_rule_runs.due_at = cards.due_at)").
+		Order("cards.due_at ASC").
+		Scan(&cards).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// RunDueRules records the run and applies the due date rules of the board of the card in the same transaction
+func (repo AutomationRepository) RunDueRules(card *models.DueCard, now time.Time) error {
+	tx := repo.db.Begin()
+
+	err := tx.Create(&models.DueRuleRun{CardID: card.CardID, DueAt: card.DueAt, RunAt: now}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = repo.engine.Run(models.Context{UserId: models.AutomationUserID}, tx, Event{
+		Trigger: models.RuleTriggerDuePassed,
+		BoardID: card.BoardID,
+		CardID:  card.CardID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
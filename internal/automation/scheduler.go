@@ -0,0 +1,72 @@
+package automation
+
+import (
+	"context"
+	"time"
+	"trellode-go/internal/log"
+	"trellode-go/internal/utils/schedule"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultInterval = time.Minute
+
+// Scheduler periodically runs the rules triggered by passed due dates
+type Scheduler struct {
+	repo     AutomationRepositoryInterface
+	interval time.Duration
+	log      *zap.Logger
+}
+
+func NewScheduler(repo AutomationRepositoryInterface, interval time.Duration, log *zap.Logger) Scheduler {
+	return Scheduler{
+		repo:     repo,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// NewSchedulerFromEnv returns a scheduler running every AUTOMATION_INTERVAL (a duration, "none" to disable due date rules)
+func NewSchedulerFromEnv(db *gorm.DB, logger *zap.Logger) (Scheduler, error) {
+	interval, err := schedule.Interval("AUTOMATION_INTERVAL", defaultInterval)
+	if err != nil {
+		return Scheduler{}, err
+	}
+
+	logService := log.NewLogService(log.NewLogRepository(db, logger))
+	engine := NewEngine(logService, logger)
+	return NewScheduler(NewAutomationRepository(db, logger, engine), interval, logger), nil
+}
+
+// Run runs the due date rules every interval until ctx is done
+func (s Scheduler) Run(ctx context.Context) {
+	schedule.Task{
+		Interval: s.interval,
+		Job:      s.RunDueRules,
+		Disabled: "Due date rules are disabled",
+		Failed:   "Failed to run due date rules",
+		Done:     "Ran due date rules on %d card(s)",
+	}.Run(ctx, s.log)
+}
+
+// RunDueRules runs the rules of the cards whose due date passed before now and returns on how many cards they ran
+func (s Scheduler) RunDueRules(now time.Time) (int, error) {
+	cards, err := s.repo.GetPassedDueCards(now)
+	if err != nil {
+		return 0, err
+	}
+
+	run := 0
+	for _, card := range cards {
+		err = s.repo.RunDueRules(card, now)
+		if err != nil {
+			// try the other cards, this one is retried on next run
+			s.log.Error("Failed to run due date rules of card " + card.CardID + ": " + err.Error())
+			continue
+		}
+		run++
+	}
+
+	return run, nil
+}
@@ -0,0 +1,54 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"trellode-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	cards   []*models.DueCard
+	failing string
+	run     []string
+}
+
+func (repo *fakeRepository) GetPassedDueCards(now time.Time) ([]*models.DueCard, error) {
+	cards := []*models.DueCard{}
+	for _, card := range repo.cards {
+		if !card.DueAt.After(now) {
+			cards = append(cards, card)
+		}
+	}
+	return cards, nil
+}
+
+func (repo *fakeRepository) RunDueRules(card *models.DueCard, now time.Time) error {
+	if card.CardID == repo.failing {
+		return errors.New("rule failed")
+	}
+	repo.run = append(repo.run, card.CardID)
+	return nil
+}
+
+func TestRunDueRules(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{
+		cards: []*models.DueCard{
+			{CardID: "passed", DueAt: now.Add(-time.Hour)},
+			{CardID: "failing", DueAt: now.Add(-time.Minute)},
+			{CardID: "now", DueAt: now},
+			{CardID: "future", DueAt: now.Add(time.Minute)},
+		},
+		failing: "failing",
+	}
+	scheduler := NewScheduler(repo, time.Minute, zap.NewNop())
+
+	run, err := scheduler.RunDueRules(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, run)
+	assert.Equal(t, []string{"passed", "now"}, repo.run)
+}
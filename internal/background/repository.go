@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/base64"
 	"errors"
-	"fmt"
 	"image"
 	"image/color"
 	"image/jpeg"
@@ -13,10 +12,10 @@ import (
 	"strings"
 	"trellode-go/internal/log"
 	"trellode-go/internal/models"
+	"trellode-go/internal/utils/imaging"
 	"trellode-go/internal/utils/messages"
 
 	"github.com/google/uuid"
-	"github.com/nfnt/resize"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -92,21 +91,12 @@ func (repo BackgroundRepository) CreateBackground(context models.Context, base64
 		return "", http.StatusInternalServerError, err
 	}
 
-	// calculate dominant color of an image
-	img, _, err := image.Decode(bytes.NewReader(decoded))
+	// resize and calculate average color of the image
+	resizedImg, averageColor, err := imaging.Thumbnail(decoded, 1920)
 	if err != nil {
 		return "", http.StatusInternalServerError, err
 	}
-
-	// resize
-	newWidth := 1920
-	newHeight := 0 // Set to 0 to preserve aspect ratio
-	resizedImg := resize.Resize(uint(newWidth), uint(newHeight), img, resize.Lanczos3)
-
-	averageColor := averageColor(resizedImg)
-	r, g, b, _ := averageColor.RGBA()
-	colorCss := fmt.Sprintf("#%02x%02x%02x", uint8(r>>8), uint8(g>>8), uint8(b>>8))
-	background.Color = colorCss
+	background.Color = imaging.ToCSS(averageColor)
 
 	// reencode to base64
 	var buf bytes.Buffer
@@ -210,26 +200,3 @@ func dominantColor(img image.Image) color.Color {
 
 	return dominant
 }
-
-func averageColor(img image.Image) color.Color {
-	bounds := img.Bounds()
-	var rTotal, gTotal, bTotal, count uint32
-
-	// Iterate over each pixel
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			r, g, b, _ := img.At(x, y).RGBA()
-			rTotal += r >> 8
-			gTotal += g >> 8
-			bTotal += b >> 8
-			count++
-		}
-	}
-
-	// Calculate average values
-	avgR := uint8(rTotal / count)
-	avgG := uint8(gTotal / count)
-	avgB := uint8(bTotal / count)
-
-	return color.RGBA{R: avgR, G: avgG, B: avgB, A: 255}
-}
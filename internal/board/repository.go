@@ -3,15 +3,21 @@ package board
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"image/color"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 	"trellode-go/internal/log"
+	"trellode-go/internal/member/access"
 	"trellode-go/internal/models"
+	"trellode-go/internal/utils/clone"
+	"trellode-go/internal/utils/commentcount"
+	"trellode-go/internal/utils/imaging"
 	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/rank"
+	"trellode-go/internal/utils/reaction"
+	"trellode-go/internal/utils/storage"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -22,43 +28,96 @@ type BoardRepository struct {
 	db         *gorm.DB
 	log        *zap.Logger
 	logService log.LogService
+	storage    storage.Storage
 }
 
 type BoardRepositoryInterface interface {
-	GetBoard(models.Context, string) (*models.Board, int, error)
-	GetBoards(models.Context, bool) ([]*models.Board, int, error)
+	GetBoard(models.Context, string, models.CardFilter) (*models.Board, int, error)
+	GetBoards(models.Context, bool, string) ([]*models.Board, int, error)
 	CreateBoard(models.Context, *models.Board) (string, int, error)
 	UpdateBoard(models.Context, *models.Board) (int, error)
+	SetWorkspace(models.Context, string, string) (int, error)
 	UpdateListsOrder(models.Context, string, string) (int, error)
 	DeleteBoard(models.Context, string) (int, error)
+	CopyBoard(models.Context, string, string, bool) (string, int, error)
+
+	GetLabels(models.Context, string) ([]*models.Label, int, error)
+	GetLabel(models.Context, string) (*models.Label, int, error)
+	CreateLabel(models.Context, *models.Label) (string, int, error)
+	UpdateLabel(models.Context, *models.Label) (int, error)
+	DeleteLabel(models.Context, string) (int, error)
+
+	GetCustomFields(models.Context, string) ([]*models.CustomField, int, error)
+	GetCustomField(models.Context, string) (*models.CustomField, int, error)
+	CreateCustomField(models.Context, *models.CustomField) (string, int, error)
+	UpdateCustomField(models.Context, *models.CustomField) (int, error)
+	DeleteCustomField(models.Context, string) (int, error)
+
+	GetRules(models.Context, string) ([]*models.Rule, int, error)
+	GetRule(models.Context, string) (*models.Rule, int, error)
+	CreateRule(models.Context, *models.Rule) (string, int, error)
+	UpdateRule(models.Context, *models.Rule) (int, error)
+	DeleteRule(models.Context, string) (int, error)
 }
 
-func NewBoardRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) BoardRepository {
+func NewBoardRepository(db *gorm.DB, log *zap.Logger, logService log.LogService, storage storage.Storage) BoardRepository {
 	return BoardRepository{
 		db:         db,
 		log:        log,
 		logService: logService,
+		storage:    storage,
 	}
 }
 
-func (repo BoardRepository) GetBoard(context models.Context, id string) (*models.Board, int, error) {
+// GetBoard returns a board with its lists and cards, only the cards matching filter are returned
+func (repo BoardRepository) GetBoard(context models.Context, id string, filter models.CardFilter) (*models.Board, int, error) {
+	if filter.Due != "" && filter.Due != models.CardDueOverdue && filter.Due != models.CardDueThisWeek {
+		return nil, http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidDueFilter"))
+	}
+	now := time.Now()
+
 	var board *models.Board
 	err := repo.db.
 		Preload("Background").
+		Preload("Labels", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name ASC")
+		}).
+		Preload("CustomFields", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Preload("Lists", func(db *gorm.DB) *gorm.DB {
-			return db.Where("archived_at IS NULL").Order("position ASC")
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
 		}).
 		Preload("Lists.Cards", func(db *gorm.DB) *gorm.DB {
-			return db.Where("archived_at IS NULL").Order("position ASC")
+			db = db.Where("archived_at IS NULL")
+			if len(filter.LabelIDs) > 0 {
+				db = db.Where("id IN (SELECT card_id FROM card_labels WHERE label_id IN ?)", filter.LabelIDs)
+			}
+			switch filter.Due {
+			case models.CardDueOverdue:
+				db = db.Where("due_at < ? AND completed_at IS NULL", now)
+			case models.CardDueThisWeek:
+				weekStart, weekEnd := weekBounds(now)
+				db = db.Where("due_at >= ? AND due_at < ?", weekStart, weekEnd)
+			}
+			return db.Order("rank_key ASC")
 		}).
-		Preload("Lists.Cards.Comments", func(db *gorm.DB) *gorm.DB {
+		Preload("Lists.Cards.Labels", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name ASC")
+		}).
+		Preload("Lists.Cards.Assignees", func(db *gorm.DB) *gorm.DB {
+			return db.Order("firstname ASC, lastname ASC")
+		}).
+		Preload("Lists.Cards.Attachments", func(db *gorm.DB) *gorm.DB {
 			return db.Order("created_at DESC")
 		}).
+		Preload("Lists.Cards.CustomFieldValues").
+		Preload("Lists.Cards.Recurrence").
 		Preload("Lists.Cards.Checklists", func(db *gorm.DB) *gorm.DB {
 			return db.Where("archived_at IS NULL").Order("created_at DESC")
 		}).
 		Preload("Lists.Cards.Checklists.Items", func(db *gorm.DB) *gorm.DB {
-			return db.Order("position ASC")
+			return db.Order("rank_key ASC")
 		}).
 		Where("id = ?", id).
 		First(&board).Error
@@ -68,6 +127,39 @@ func (repo BoardRepository) GetBoard(context models.Context, id string) (*models
 	if board.ID == "" {
 		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
 	}
+	models.NumberLists(board.Lists)
+
+	// open cards are counted whatever the filter, to be compared to the WIP limits
+	counts := []struct {
+		ListID string
+		Count  int
+	}{}
+	err = repo.db.Model(&models.Card{}).
+		Select("list_id, COUNT(*) AS count").
+		Where("list_id IN (SELECT id FROM lists WHERE board_id = ?) AND archived_at IS NULL", board.ID).
+		Group("list_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for _, count := range counts {
+		for i := range board.Lists {
+			if board.Lists[i].ID == count.ListID {
+				board.Lists[i].CardCount = count.Count
+			}
+		}
+	}
+
+	// comments are loaded page by page, the board only gives how many there are
+	commentCountOfCard, err := commentcount.Load(repo.db, "SELECT cards.id FROM cards JOIN lists ON lists.id = cards.list_id WHERE lists.board_id = ?", board.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for i := range board.Lists {
+		for j := range board.Lists[i].Cards {
+			board.Lists[i].Cards[j].CommentCount = commentCountOfCard[board.Lists[i].Cards[j].ID]
+		}
+	}
 
 	if board.Background != nil {
 		//base64String := base64.StdEncoding.EncodeToString(board.Background.Data)
@@ -78,21 +170,20 @@ func (repo BoardRepository) GetBoard(context models.Context, id string) (*models
 		if err != nil {
 			return nil, http.StatusInternalServerError, err
 		}
-		board.MenuColorDark = colorToCSS(menuColorDark)
+		board.MenuColorDark = imaging.ToCSS(menuColorDark)
 		menuColorLight, err := darkenColor(board.Background.Color, 0.7)
 		if err != nil {
 			return nil, http.StatusInternalServerError, err
 		}
-		board.MenuColorLight = colorToCSS(menuColorLight)
+		board.MenuColorLight = imaging.ToCSS(menuColorLight)
 		listColor, err := lightenColor(board.Background.Color, 0.5)
 		if err != nil {
 			return nil, http.StatusInternalServerError, err
 		}
-		board.ListColor = colorToCSS(listColor)
+		board.ListColor = imaging.ToCSS(listColor)
 	}
 
 	// set openedAt
-	now := time.Now()
 	err = repo.db.Model(&models.Board{}).Where("id = ?", board.ID).Update("opened_at", now.Format("2006-01-02 15:04:05")).Error
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
@@ -101,19 +192,28 @@ func (repo BoardRepository) GetBoard(context models.Context, id string) (*models
 	return board, http.StatusOK, nil
 }
 
-func (repo BoardRepository) GetBoards(context models.Context, archived bool) ([]*models.Board, int, error) {
+// GetBoards returns the boards the user has access to. workspaceId restricts them to a workspace,
+// "none" to the boards outside of any workspace and "" returns all of them.
+func (repo BoardRepository) GetBoards(context models.Context, archived bool, workspaceId string) ([]*models.Board, int, error) {
 	boards := []*models.Board{}
 
-	sql := "user_id = ? AND archived_at IS NULL"
+	// boards on which the user has a role
+	sql := "id IN (?) AND archived_at IS NULL"
 	if archived {
-		sql = "user_id = ? AND archived_at IS NOT NULL"
+		sql = "id IN (?) AND archived_at IS NOT NULL"
 	}
-	err := repo.db.
+	query := repo.db.
 		Preload("Background").
 		//Preload("Lists", repo.db.Where("archived_at IS NULL")).
 		//Preload("Lists.Cards", repo.db.Where("archived_at IS NULL")).
 		//Preload("Lists.Cards.Comments").
-		Where(sql, context.UserId).
+		Where(sql, access.VisibleBoards(repo.db, context.UserId))
+	if workspaceId == "none" {
+		query = query.Where("workspace_id IS NULL")
+	} else if workspaceId != "" {
+		query = query.Where("workspace_id = ?", workspaceId)
+	}
+	err := query.
 		Order("title ASC").
 		Find(&boards).Error
 	if err != nil {
@@ -135,14 +235,29 @@ func (repo BoardRepository) CreateBoard(context models.Context, board *models.Bo
 	board.ID = uuid.NewString()
 	board.UserID = context.UserId
 	board.ArchivedAt = nil
+	board.IsTemplate = false
+	if board.WorkspaceID != nil && *board.WorkspaceID == "" {
+		board.WorkspaceID = nil
+	}
 
 	tx := repo.db.Begin()
 
-	err := tx.Omit("BackgroundID", "Background", "Lists").Create(&board).Error
+	err := tx.Omit("BackgroundID", "Background", "Lists", "Labels", "CustomFields").Create(&board).Error
 	if err != nil {
 		return "", http.StatusInternalServerError, err
 	}
 
+	// creator is owner of the board
+	err = tx.Create(&models.BoardMember{
+		BoardID: board.ID,
+		UserID:  context.UserId,
+		Role:    models.BoardRoleOwner,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
 	// log operation
 	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
 		UserID:         context.UserId,
@@ -162,7 +277,7 @@ func (repo BoardRepository) CreateBoard(context models.Context, board *models.Bo
 
 func (repo BoardRepository) UpdateBoard(context models.Context, board *models.Board) (int, error) {
 	// get board from db
-	boardBefore, severity, err := repo.GetBoard(context, board.ID)
+	boardBefore, severity, err := repo.GetBoard(context, board.ID, models.CardFilter{})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return severity, err
 	}
@@ -190,7 +305,7 @@ func (repo BoardRepository) UpdateBoard(context models.Context, board *models.Bo
 
 	tx := repo.db.Begin()
 
-	err = tx.Omit("UserID", "Background", "Lists", "CreatedAt").Save(&board).Error
+	err = tx.Omit("UserID", "WorkspaceID", "Background", "Lists", "Labels", "CustomFields", "CreatedAt", "IsTemplate").Save(&board).Error
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -220,9 +335,64 @@ func (repo BoardRepository) UpdateBoard(context models.Context, board *models.Bo
 	return http.StatusAccepted, nil
 }
 
+// SetWorkspace moves a board into a workspace, or out of its workspace if workspaceId is empty
+func (repo BoardRepository) SetWorkspace(context models.Context, boardId string, workspaceId string) (int, error) {
+	var board models.Board
+	err := repo.db.Where("id = ?", boardId).First(&board).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if board.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	}
+
+	fromValue := ""
+	if board.WorkspaceID != nil {
+		fromValue = *board.WorkspaceID
+	}
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "workspaceid",
+		FromValue: fromValue,
+		ToValue:   workspaceId,
+	}})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	var value interface{}
+	if workspaceId != "" {
+		value = workspaceId
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.Board{}).Where("id = ?", boardId).Update("workspace_id", value).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "updateboard",
+		ActionTargetID: boardId,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
 func (repo BoardRepository) UpdateListsOrder(context models.Context, boardId string, idsOrdered string) (int, error) {
 	// get list from db
-	board, severity, err := repo.GetBoard(context, boardId)
+	board, severity, err := repo.GetBoard(context, boardId, models.CardFilter{})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return severity, err
 	}
@@ -230,23 +400,31 @@ func (repo BoardRepository) UpdateListsOrder(context models.Context, boardId str
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
 	}
 
-	tx := repo.db.Begin()
-
 	idsOrderedSplit := strings.Split(idsOrdered, ",")
 
-	for i, id := range idsOrderedSplit {
-		var list *models.List
-		err := tx.Where("id = ?", id).First(&list).Error
-		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
-		}
-		if list.ID == "" {
-			tx.Rollback()
+	lists := []models.List{}
+	err = repo.db.Where("board_id = ? AND id IN ?", boardId, idsOrderedSplit).Find(&lists).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	listRanks := map[string]string{}
+	for _, list := range lists {
+		listRanks[list.ID] = list.Rank
+	}
+	orderedRanks := []string{}
+	for _, id := range idsOrderedSplit {
+		listRank, ok := listRanks[id]
+		if !ok {
 			return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
 		}
-		list.Position = i + 1
-		err = tx.Save(&list).Error
+		orderedRanks = append(orderedRanks, listRank)
+	}
+
+	tx := repo.db.Begin()
+
+	// only the lists out of order get a new rank
+	for i, newRank := range rank.Reorder(orderedRanks) {
+		err = tx.Model(&models.List{}).Where("id = ?", idsOrderedSplit[i]).Update("rank_key", newRank).Error
 		if err != nil {
 			tx.Rollback()
 			return http.StatusInternalServerError, err
@@ -271,7 +449,7 @@ func (repo BoardRepository) UpdateListsOrder(context models.Context, boardId str
 }
 
 func (repo BoardRepository) DeleteBoard(context models.Context, id string) (int, error) {
-	board, severity, err := repo.GetBoard(context, id)
+	board, severity, err := repo.GetBoard(context, id, models.CardFilter{})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return severity, err
 	}
@@ -281,24 +459,65 @@ func (repo BoardRepository) DeleteBoard(context models.Context, id string) (int,
 
 	tx := repo.db.Begin()
 
-	// remove comments
+	// remove reactions on all cards and their comments
+	err = reaction.DeleteOfCards(tx, "SELECT cards.id FROM cards JOIN lists ON lists.id = cards.list_id WHERE lists.board_id = ?", board.ID)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove comments with their revisions
+	err = tx.Where("comment_id IN (SELECT comments.id FROM comments JOIN cards ON cards.id = comments.card_id JOIN lists ON lists.id = cards.list_id WHERE lists.board_id = ?)", board.ID).Delete(&models.CommentRevision{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("card_id IN (SELECT cards.id FROM cards JOIN lists ON lists.id = cards.list_id WHERE lists.board_id = ?)", board.ID).Delete(&models.Comment{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove attachments of all cards (archived ones included), their data is removed once the board is gone
+	boardCards := "card_id IN (SELECT cards.id FROM cards JOIN lists ON lists.id = cards.list_id WHERE lists.board_id = ?)"
+	attachments := []*models.Attachment{}
+	err = tx.Where(boardCards, board.ID).Find(&attachments).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where(boardCards, board.ID).Delete(&models.Attachment{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove links from and to all cards
+	boardCardIds := "SELECT cards.id FROM cards JOIN lists ON lists.id = cards.list_id WHERE lists.board_id = ?"
+	err = tx.Where("card_id IN ("+boardCardIds+") OR linked_card_id IN ("+boardCardIds+")", board.ID, board.ID).Delete(&models.CardLink{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove recurrences of all cards
+	err = tx.Where("card_id IN ("+boardCardIds+")", board.ID).Delete(&models.CardRecurrence{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove notifications
+	err = tx.Where("board_id = ?", board.ID).Delete(&models.Notification{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove cards with their assignees
 	lists := board.Lists
 	for _, list := range lists {
 		cards := list.Cards
 		for _, card := range cards {
-			for _, comment := range card.Comments {
-				err = tx.Delete(&comment).Error
-				if err != nil {
-					tx.Rollback()
-					return http.StatusInternalServerError, err
-				}
+			err = tx.Where("card_id = ?", card.ID).Delete(&models.CardAssignee{}).Error
+			if err != nil {
+				tx.Rollback()
+				return http.StatusInternalServerError, err
 			}
-		}
-	}
-	// remove cards
-	for _, list := range lists {
-		cards := list.Cards
-		for _, card := range cards {
 			err = tx.Delete(&card).Error
 			if err != nil {
 				tx.Rollback()
@@ -314,6 +533,40 @@ func (repo BoardRepository) DeleteBoard(context models.Context, id string) (int,
 			return http.StatusInternalServerError, err
 		}
 	}
+	// remove labels
+	err = tx.Where("label_id IN (SELECT id FROM labels WHERE board_id = ?)", board.ID).Delete(&models.CardLabel{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("board_id = ?", board.ID).Delete(&models.Label{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove custom fields
+	err = tx.Where("custom_field_id IN (SELECT id FROM custom_fields WHERE board_id = ?)", board.ID).Delete(&models.CustomFieldValue{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("board_id = ?", board.ID).Delete(&models.CustomField{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove rules
+	err = tx.Where("board_id = ?", board.ID).Delete(&models.Rule{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove members
+	err = tx.Where("board_id = ?", board.ID).Delete(&models.BoardMember{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
 	// remove board
 	err = tx.Delete(&board).Error
 	if err != nil {
@@ -334,91 +587,797 @@ func (repo BoardRepository) DeleteBoard(context models.Context, id string) (int,
 
 	tx.Commit()
 
+	for _, attachment := range attachments {
+		err = repo.storage.Delete(attachment.StorageKey)
+		if err != nil {
+			repo.log.Warn("Failed to delete attachment data " + attachment.StorageKey + ": " + err.Error())
+		}
+	}
+
 	return http.StatusAccepted, nil
 }
 
-func darkenColor(colorCss string, factor float64) (color.Color, error) {
-	c, err := parseHexColor(colorCss)
-	if err != nil {
-		return nil, err
+// CopyBoard deep-copies a board (lists, cards, checklists with items and optionally comments) into a new board owned by the current user
+func (repo BoardRepository) CopyBoard(context models.Context, id string, title string, withComments bool) (string, int, error) {
+	var source models.Board
+	err := repo.db.
+		Preload("Lists", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards.Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		}).
+		Preload("Lists.Cards.Checklists", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("created_at DESC")
+		}).
+		Preload("Lists.Cards.Checklists.Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards.Labels").
+		Preload("Lists.Cards.CustomFieldValues").
+		Preload("Labels").
+		Preload("CustomFields").
+		Where("id = ?", id).
+		First(&source).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
 	}
-	if factor < 0 || factor > 1 {
-		return nil, errors.New("factor must be between 0 and 1")
+	if source.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
 	}
 
-	r, g, b, a := c.RGBA()
-	var newR, newG, newB uint8
-	// Convert to 8-bit values and apply the darkening factor
-	newR = uint8(float64(uint8(r>>8)) * factor)
-	newG = uint8(float64(uint8(g>>8)) * factor)
-	newB = uint8(float64(uint8(b>>8)) * factor)
-	// Return the darkened color with the original alpha value
-	return color.RGBA{R: newR, G: newG, B: newB, A: uint8(a >> 8)}, nil
-}
-
-func lightenColor(colorCss string, factor float64) (color.Color, error) {
-	c, err := parseHexColor(colorCss)
-	if err != nil {
-		return nil, err
+	if title == "" {
+		title = source.Title
 	}
-	if factor < 0 || factor > 1 {
-		panic("factor must be between 0 and 1")
+	board := models.Board{
+		ID:           uuid.NewString(),
+		UserID:       context.UserId,
+		Title:        title,
+		BackgroundID: source.BackgroundID,
 	}
 
-	r, g, b, a := c.RGBA()
-	// Convert to 8-bit values
-	red := uint8(r >> 8)
-	green := uint8(g >> 8)
-	blue := uint8(b >> 8)
+	tx := repo.db.Begin()
 
-	// Interpolate towards white
-	lightenedR := uint8(float64(red) + (255-float64(red))*factor)
-	lightenedG := uint8(float64(green) + (255-float64(green))*factor)
-	lightenedB := uint8(float64(blue) + (255-float64(blue))*factor)
+	omitted := []string{"Background", "Lists", "Labels", "CustomFields"}
+	if board.BackgroundID == "" {
+		omitted = append(omitted, "BackgroundID")
+	}
+	err = tx.Omit(omitted...).Create(&board).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
 
-	// Return the lightened color with the original alpha value
-	return color.RGBA{R: lightenedR, G: lightenedG, B: lightenedB, A: uint8(a >> 8)}, nil
-}
+	// creator is owner of the board
+	err = tx.Create(&models.BoardMember{
+		BoardID: board.ID,
+		UserID:  context.UserId,
+		Role:    models.BoardRoleOwner,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
 
-func parseHexColor(s string) (color.Color, error) {
-	// Remove the '#' if it exists
-	s = strings.TrimPrefix(s, "#")
+	mapping, err := clone.BoardSettings(tx, &source, board.ID)
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
 
-	var r, g, b, a uint8
-	var err error
+	// lists keep their ranks
+	for _, list := range source.Lists {
+		_, err := clone.List(tx, &list, board.ID, list.Rank, withComments, mapping)
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+	}
 
-	// #RRGGBB
-	r, err = parseHexByte(s[0:2])
+	// log operation
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "copiedfrom",
+		FromValue: source.ID,
+		ToValue:   board.ID,
+	}})
 	if err != nil {
-		return nil, err
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
 	}
-	g, err = parseHexByte(s[2:4])
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        board.ID,
+		Action:         "copyboard",
+		ActionTargetID: board.ID,
+		Changes:        string(changesJson),
+	})
 	if err != nil {
-		return nil, err
+		tx.Rollback()
+		return "", severity, err
 	}
-	b, err = parseHexByte(s[4:6])
+
+	tx.Commit()
+
+	return board.ID, http.StatusCreated, nil
+}
+
+func (repo BoardRepository) GetLabels(context models.Context, boardId string) ([]*models.Label, int, error) {
+	labels := []*models.Label{}
+	err := repo.db.
+		Where("board_id = ?", boardId).
+		Order("name ASC").
+		Find(&labels).Error
 	if err != nil {
-		return nil, err
+		return nil, http.StatusInternalServerError, err
 	}
-	a = 255 // fully opaque
 
-	return color.RGBA{R: r, G: g, B: b, A: a}, nil
+	return labels, http.StatusOK, nil
 }
 
-func parseHexByte(s string) (uint8, error) {
-	v, err := strconv.ParseUint(s, 16, 8)
-	if err != nil {
-		return 0, err
+func (repo BoardRepository) GetLabel(context models.Context, id string) (*models.Label, int, error) {
+	var label models.Label
+	err := repo.db.Where("id = ?", id).First(&label).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
 	}
-	return uint8(v), nil
+	if label.ID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "LabelNotFound"))
+	}
+
+	return &label, http.StatusOK, nil
 }
 
-// colorToCSS converts a color.Color to a CSS color string.
-//
-// It takes a color.Color as a parameter and returns a string representing the CSS color value.
-func colorToCSS(c color.Color) string {
-	r, g, b, _ := c.RGBA()
-	return fmt.Sprintf("#%02x%02x%02x", uint8(r>>8), uint8(g>>8), uint8(b>>8))
+func (repo BoardRepository) CreateLabel(context models.Context, label *models.Label) (string, int, error) {
+	_, err := imaging.ParseHexColor(label.Color)
+	if err != nil {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidColor"))
+	}
+
+	label.ID = uuid.NewString()
+
+	tx := repo.db.Begin()
+
+	err = tx.Create(&label).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        label.BoardID,
+		Action:         "createlabel",
+		ActionTargetID: label.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return label.ID, http.StatusCreated, nil
+}
+
+func (repo BoardRepository) UpdateLabel(context models.Context, label *models.Label) (int, error) {
+	_, err := imaging.ParseHexColor(label.Color)
+	if err != nil {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidColor"))
+	}
+
+	labelBefore, severity, err := repo.GetLabel(context, label.ID)
+	if err != nil {
+		return severity, err
+	}
+
+	changes := []*models.LogChange{}
+	if labelBefore.Name != label.Name {
+		changes = append(changes, &models.LogChange{
+			Field:     "name",
+			FromValue: labelBefore.Name,
+			ToValue:   label.Name,
+		})
+	}
+	if labelBefore.Color != label.Color {
+		changes = append(changes, &models.LogChange{
+			Field:     "color",
+			FromValue: labelBefore.Color,
+			ToValue:   label.Color,
+		})
+	}
+	// marshal changes to JSON string
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.Label{}).
+		Where("id = ?", label.ID).
+		Updates(map[string]interface{}{"name": label.Name, "color": label.Color, "updated_at": time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        labelBefore.BoardID,
+		Action:         "updatelabel",
+		ActionTargetID: label.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// DeleteLabel removes a label from the board and from all its cards
+func (repo BoardRepository) DeleteLabel(context models.Context, id string) (int, error) {
+	label, severity, err := repo.GetLabel(context, id)
+	if err != nil {
+		return severity, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Where("label_id = ?", id).Delete(&models.CardLabel{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("id = ?", id).Delete(&models.Label{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "name",
+		FromValue: label.Name,
+	}})
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        label.BoardID,
+		Action:         "deletelabel",
+		ActionTargetID: id,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+func (repo BoardRepository) GetCustomFields(context models.Context, boardId string) ([]*models.CustomField, int, error) {
+	fields := []*models.CustomField{}
+	err := repo.db.
+		Where("board_id = ?", boardId).
+		Order("position ASC").
+		Find(&fields).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return fields, http.StatusOK, nil
+}
+
+func (repo BoardRepository) GetCustomField(context models.Context, id string) (*models.CustomField, int, error) {
+	var field models.CustomField
+	err := repo.db.Where("id = ?", id).First(&field).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if field.ID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CustomFieldNotFound"))
+	}
+
+	return &field, http.StatusOK, nil
+}
+
+func (repo BoardRepository) CreateCustomField(context models.Context, field *models.CustomField) (string, int, error) {
+	if !isValidCustomFieldType(field.Type) {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidCustomFieldType"))
+	}
+	if field.Type == models.CustomFieldTypeDropdown && len(field.Options) == 0 {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "CustomFieldOptionsRequired"))
+	}
+	if field.Type != models.CustomFieldTypeDropdown {
+		field.Options = nil
+	}
+
+	// new field goes last
+	var count int64
+	err := repo.db.Model(&models.CustomField{}).Where("board_id = ?", field.BoardID).Count(&count).Error
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	field.ID = uuid.NewString()
+	field.Position = int(count) + 1
+
+	tx := repo.db.Begin()
+
+	err = tx.Create(&field).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        field.BoardID,
+		Action:         "createcustomfield",
+		ActionTargetID: field.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return field.ID, http.StatusCreated, nil
+}
+
+// UpdateCustomField renames a custom field or changes the options of a dropdown, the type of a field cannot change.
+// Cards set to a removed option lose their value.
+func (repo BoardRepository) UpdateCustomField(context models.Context, field *models.CustomField) (int, error) {
+	fieldBefore, severity, err := repo.GetCustomField(context, field.ID)
+	if err != nil {
+		return severity, err
+	}
+	if field.Type != "" && field.Type != fieldBefore.Type {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "CustomFieldTypeChange"))
+	}
+	if fieldBefore.Type == models.CustomFieldTypeDropdown && len(field.Options) == 0 {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "CustomFieldOptionsRequired"))
+	}
+	if fieldBefore.Type != models.CustomFieldTypeDropdown {
+		field.Options = nil
+	}
+
+	changes := []*models.LogChange{}
+	if fieldBefore.Name != field.Name {
+		changes = append(changes, &models.LogChange{
+			Field:     "name",
+			FromValue: fieldBefore.Name,
+			ToValue:   field.Name,
+		})
+	}
+	if strings.Join(fieldBefore.Options, ", ") != strings.Join(field.Options, ", ") {
+		changes = append(changes, &models.LogChange{
+			Field:     "options",
+			FromValue: strings.Join(fieldBefore.Options, ", "),
+			ToValue:   strings.Join(field.Options, ", "),
+		})
+	}
+	// marshal changes to JSON string
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	// struct update so that options go through their JSON serializer
+	err = tx.Model(&models.CustomField{}).
+		Where("id = ?", field.ID).
+		Select("Name", "Options", "UpdatedAt").
+		Updates(&models.CustomField{Name: field.Name, Options: field.Options, UpdatedAt: time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	if fieldBefore.Type == models.CustomFieldTypeDropdown {
+		err = tx.Where("custom_field_id = ? AND value NOT IN ?", field.ID, field.Options).Delete(&models.CustomFieldValue{}).Error
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        fieldBefore.BoardID,
+		Action:         "updatecustomfield",
+		ActionTargetID: field.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// DeleteCustomField removes a custom field from the board and its values from all cards
+func (repo BoardRepository) DeleteCustomField(context models.Context, id string) (int, error) {
+	field, severity, err := repo.GetCustomField(context, id)
+	if err != nil {
+		return severity, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Where("custom_field_id = ?", id).Delete(&models.CustomFieldValue{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("id = ?", id).Delete(&models.CustomField{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// close the gap in positions
+	err = tx.Model(&models.CustomField{}).
+		Where("board_id = ? AND position > ?", field.BoardID, field.Position).
+		Update("position", gorm.Expr("position - 1")).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "name",
+		FromValue: field.Name,
+	}})
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        field.BoardID,
+		Action:         "deletecustomfield",
+		ActionTargetID: id,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+func (repo BoardRepository) GetRules(context models.Context, boardId string) ([]*models.Rule, int, error) {
+	rules := []*models.Rule{}
+	err := repo.db.
+		Where("board_id = ?", boardId).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return rules, http.StatusOK, nil
+}
+
+func (repo BoardRepository) GetRule(context models.Context, id string) (*models.Rule, int, error) {
+	var rule models.Rule
+	err := repo.db.Where("id = ?", id).First(&rule).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if rule.ID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "RuleNotFound"))
+	}
+
+	return &rule, http.StatusOK, nil
+}
+
+func (repo BoardRepository) CreateRule(context models.Context, rule *models.Rule) (string, int, error) {
+	severity, err := repo.checkRule(context, rule)
+	if err != nil {
+		return "", severity, err
+	}
+
+	rule.ID = uuid.NewString()
+	// rules are disabled through an update
+	rule.Enabled = true
+
+	tx := repo.db.Begin()
+
+	err = tx.Create(&rule).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        rule.BoardID,
+		Action:         "createrule",
+		ActionTargetID: rule.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return rule.ID, http.StatusCreated, nil
+}
+
+// UpdateRule changes the name, trigger, action or enabled state of a rule, a rule cannot move to another board
+func (repo BoardRepository) UpdateRule(context models.Context, rule *models.Rule) (int, error) {
+	ruleBefore, severity, err := repo.GetRule(context, rule.ID)
+	if err != nil {
+		return severity, err
+	}
+	rule.BoardID = ruleBefore.BoardID
+	severity, err = repo.checkRule(context, rule)
+	if err != nil {
+		return severity, err
+	}
+
+	changes := []*models.LogChange{}
+	if ruleBefore.Name != rule.Name {
+		changes = append(changes, &models.LogChange{
+			Field:     "name",
+			FromValue: ruleBefore.Name,
+			ToValue:   rule.Name,
+		})
+	}
+	if ruleBefore.Trigger != rule.Trigger || ruleBefore.TriggerListID != rule.TriggerListID {
+		changes = append(changes, &models.LogChange{
+			Field:     "trigger",
+			FromValue: strings.TrimSpace(ruleBefore.Trigger + " " + ruleBefore.TriggerListID),
+			ToValue:   strings.TrimSpace(rule.Trigger + " " + rule.TriggerListID),
+		})
+	}
+	if ruleBefore.Action != rule.Action || ruleBefore.ActionValue != rule.ActionValue {
+		changes = append(changes, &models.LogChange{
+			Field:     "action",
+			FromValue: strings.TrimSpace(ruleBefore.Action + " " + ruleBefore.ActionValue),
+			ToValue:   strings.TrimSpace(rule.Action + " " + rule.ActionValue),
+		})
+	}
+	if ruleBefore.Enabled != rule.Enabled {
+		changes = append(changes, &models.LogChange{
+			Field:     "enabled",
+			FromValue: strconv.FormatBool(ruleBefore.Enabled),
+			ToValue:   strconv.FormatBool(rule.Enabled),
+		})
+	}
+	// marshal changes to JSON string
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.Rule{}).
+		Where("id = ?", rule.ID).
+		Select("Name", "Trigger", "TriggerListID", "Action", "ActionValue", "Enabled", "UpdatedAt").
+		Updates(&models.Rule{Name: rule.Name, Trigger: rule.Trigger, TriggerListID: rule.TriggerListID, Action: rule.Action, ActionValue: rule.ActionValue, Enabled: rule.Enabled, UpdatedAt: time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        ruleBefore.BoardID,
+		Action:         "updaterule",
+		ActionTargetID: rule.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+func (repo BoardRepository) DeleteRule(context models.Context, id string) (int, error) {
+	rule, severity, err := repo.GetRule(context, id)
+	if err != nil {
+		return severity, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Where("id = ?", id).Delete(&models.Rule{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "name",
+		FromValue: rule.Name,
+	}})
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        rule.BoardID,
+		Action:         "deleterule",
+		ActionTargetID: id,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// checkRule checks the trigger and action of a rule, and that its list, label or user belong to its board
+func (repo BoardRepository) checkRule(context models.Context, rule *models.Rule) (int, error) {
+	switch rule.Trigger {
+	case models.RuleTriggerCardCreated, models.RuleTriggerCardMoved:
+	case models.RuleTriggerChecklistCompleted, models.RuleTriggerDuePassed:
+		rule.TriggerListID = ""
+	default:
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidRuleTrigger"))
+	}
+	if rule.Trigger == models.RuleTriggerCardMoved && rule.TriggerListID == "" {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "RuleListRequired"))
+	}
+	if rule.TriggerListID != "" {
+		severity, err := repo.checkRuleList(context, rule.BoardID, rule.TriggerListID)
+		if err != nil {
+			return severity, err
+		}
+	}
+
+	switch rule.Action {
+	case models.RuleActionMoveCard:
+		if rule.ActionValue == "" {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "RuleListRequired"))
+		}
+		return repo.checkRuleList(context, rule.BoardID, rule.ActionValue)
+	case models.RuleActionAddLabel:
+		var count int64
+		err := repo.db.Model(&models.Label{}).Where("id = ? AND board_id = ?", rule.ActionValue, rule.BoardID).Count(&count).Error
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if count == 0 {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "LabelNotOnBoard"))
+		}
+	case models.RuleActionAssign:
+		var count int64
+		err := repo.db.Model(&models.User{}).Where("id = ?", rule.ActionValue).Count(&count).Error
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if count == 0 {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "UserNotFound"))
+		}
+		isBoardUser, err := access.IsBoardUser(repo.db, rule.BoardID, rule.ActionValue)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if !isBoardUser {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "AssigneeNotMember"))
+		}
+	case models.RuleActionComment:
+		if strings.TrimSpace(rule.ActionValue) == "" {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "RuleCommentRequired"))
+		}
+	case models.RuleActionCheckAll, models.RuleActionArchive:
+		rule.ActionValue = ""
+	default:
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidRuleAction"))
+	}
+
+	return http.StatusOK, nil
+}
+
+func (repo BoardRepository) checkRuleList(context models.Context, boardId string, listId string) (int, error) {
+	var count int64
+	err := repo.db.Model(&models.List{}).Where("id = ? AND board_id = ?", listId, boardId).Count(&count).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if count == 0 {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "ListNotOnBoard"))
+	}
+
+	return http.StatusOK, nil
+}
+
+func isValidCustomFieldType(fieldType string) bool {
+	switch fieldType {
+	case models.CustomFieldTypeText, models.CustomFieldTypeNumber, models.CustomFieldTypeDate, models.CustomFieldTypeCheckbox, models.CustomFieldTypeDropdown:
+		return true
+	}
+	return false
+}
+
+// weekBounds returns the start of the monday of the week of t and the start of the following monday
+func weekBounds(t time.Time) (time.Time, time.Time) {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	start := time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 7)
+}
+
+func darkenColor(colorCss string, factor float64) (color.Color, error) {
+	c, err := imaging.ParseHexColor(colorCss)
+	if err != nil {
+		return nil, err
+	}
+	if factor < 0 || factor > 1 {
+		return nil, errors.New("factor must be between 0 and 1")
+	}
+
+	r, g, b, a := c.RGBA()
+	var newR, newG, newB uint8
+	// Convert to 8-bit values and apply the darkening factor
+	newR = uint8(float64(uint8(r>>8)) * factor)
+	newG = uint8(float64(uint8(g>>8)) * factor)
+	newB = uint8(float64(uint8(b>>8)) * factor)
+	// Return the darkened color with the original alpha value
+	return color.RGBA{R: newR, G: newG, B: newB, A: uint8(a >> 8)}, nil
+}
+
+func lightenColor(colorCss string, factor float64) (color.Color, error) {
+	c, err := imaging.ParseHexColor(colorCss)
+	if err != nil {
+		return nil, err
+	}
+	if factor < 0 || factor > 1 {
+		panic("factor must be between 0 and 1")
+	}
+
+	r, g, b, a := c.RGBA()
+	// Convert to 8-bit values
+	red := uint8(r >> 8)
+	green := uint8(g >> 8)
+	blue := uint8(b >> 8)
+
+	// Interpolate towards white
+	lightenedR := uint8(float64(red) + (255-float64(red))*factor)
+	lightenedG := uint8(float64(green) + (255-float64(green))*factor)
+	lightenedB := uint8(float64(blue) + (255-float64(blue))*factor)
+
+	// Return the lightened color with the original alpha value
+	return color.RGBA{R: lightenedR, G: lightenedG, B: lightenedB, A: uint8(a >> 8)}, nil
 }
 
 // whatChanged compares two Board models and returns a slice of LogChange models
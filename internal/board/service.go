@@ -3,73 +3,249 @@ package board
 import (
 	"errors"
 	"net/http"
+	"trellode-go/internal/member"
 	"trellode-go/internal/models"
 	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/workspace"
 )
 
 type BoardServiceInterface interface {
-	GetBoards(models.Context, bool) ([]*models.Board, int, error)
+	GetBoards(models.Context, bool, string) ([]*models.Board, int, error)
 	CreateBoard(models.Context, *models.Board) (string, int, error)
 	UpdateBoard(models.Context, *models.Board) (int, error)
+	SetWorkspace(models.Context, string, string) (int, error)
 	UpdateListsOrder(models.Context, string, string) (int, error)
 	DeleteBoard(models.Context, string) (int, error)
+	CopyBoard(models.Context, string, string, bool) (string, int, error)
+
+	GetLabels(models.Context, string) ([]*models.Label, int, error)
+	CreateLabel(models.Context, *models.Label) (string, int, error)
+	UpdateLabel(models.Context, *models.Label) (int, error)
+	DeleteLabel(models.Context, string) (int, error)
+
+	GetCustomFields(models.Context, string) ([]*models.CustomField, int, error)
+	CreateCustomField(models.Context, *models.CustomField) (string, int, error)
+	UpdateCustomField(models.Context, *models.CustomField) (int, error)
+	DeleteCustomField(models.Context, string) (int, error)
+
+	GetRules(models.Context, string) ([]*models.Rule, int, error)
+	CreateRule(models.Context, *models.Rule) (string, int, error)
+	UpdateRule(models.Context, *models.Rule) (int, error)
+	DeleteRule(models.Context, string) (int, error)
 }
 
 type BoardService struct {
-	repo BoardRepositoryInterface
+	repo             BoardRepositoryInterface
+	memberService    member.MemberService
+	workspaceService workspace.WorkspaceService
 }
 
 // NewPersonService returns a service to manipulate unit
-func NewBoardService(repo BoardRepositoryInterface) BoardService {
+func NewBoardService(repo BoardRepositoryInterface, memberService member.MemberService, workspaceService workspace.WorkspaceService) BoardService {
 	return BoardService{
-		repo: repo,
+		repo:             repo,
+		memberService:    memberService,
+		workspaceService: workspaceService,
 	}
 }
 
-func (s BoardService) GetBoard(context models.Context, id string) (*models.Board, int, error) {
-	return s.repo.GetBoard(context, id)
+func (s BoardService) GetBoard(context models.Context, id string, filter models.CardFilter) (*models.Board, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.GetBoard(context, id, filter)
 }
 
-func (s BoardService) GetBoards(context models.Context, archived bool) ([]*models.Board, int, error) {
-	return s.repo.GetBoards(context, archived)
+func (s BoardService) GetBoards(context models.Context, archived bool, workspaceId string) ([]*models.Board, int, error) {
+	return s.repo.GetBoards(context, archived, workspaceId)
 }
 
 func (s BoardService) CreateBoard(context models.Context, board *models.Board) (string, int, error) {
+	if board.WorkspaceID != nil && *board.WorkspaceID != "" {
+		severity, err := s.workspaceService.CheckWorkspaceRole(context, *board.WorkspaceID, models.WorkspaceRoleMember)
+		if err != nil {
+			return "", severity, err
+		}
+	}
+
 	return s.repo.CreateBoard(context, board)
 }
 
 func (s BoardService) UpdateBoard(context models.Context, id string, board *models.Board) (int, error) {
 	// check board exists
-	existingBoard, severity, err := s.GetBoard(context, id)
+	existingBoard, severity, err := s.GetBoard(context, id, models.CardFilter{})
 	if err != nil {
 		return severity, err
 	}
 	if existingBoard.ID == "" {
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
 	}
-	if existingBoard.UserID != context.UserId {
-		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "Forbidden"))
+	severity, err = s.memberService.CheckBoardRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
 	}
+	// the role is checked on the board of the path, which is the one saved
+	board.ID = id
 
 	return s.repo.UpdateBoard(context, board)
 }
 
 func (s BoardService) DeleteBoard(context models.Context, id string) (int, error) {
 	// check board exists
-	board, severity, err := s.GetBoard(context, id)
+	board, severity, err := s.GetBoard(context, id, models.CardFilter{})
 	if err != nil {
 		return severity, err
 	}
 	if board.ID == "" {
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
 	}
-	if board.UserID != context.UserId {
-		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "Forbidden"))
+	severity, err = s.memberService.CheckBoardRole(context, id, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
 	}
 
 	return s.repo.DeleteBoard(context, id)
 }
 
-func (s BoardService) UpdateListsOrder(context models.Context, listId string, idsOrdered string) (int, error) {
-	return s.repo.UpdateListsOrder(context, listId, idsOrdered)
+func (s BoardService) SetWorkspace(context models.Context, boardId string, workspaceId string) (int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, boardId, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+	if workspaceId != "" {
+		severity, err = s.workspaceService.CheckWorkspaceRole(context, workspaceId, models.WorkspaceRoleMember)
+		if err != nil {
+			return severity, err
+		}
+	}
+
+	return s.repo.SetWorkspace(context, boardId, workspaceId)
+}
+
+func (s BoardService) UpdateListsOrder(context models.Context, boardId string, idsOrdered string) (int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, boardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.UpdateListsOrder(context, boardId, idsOrdered)
+}
+
+func (s BoardService) CopyBoard(context models.Context, id string, title string, withComments bool) (string, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return s.repo.CopyBoard(context, id, title, withComments)
+}
+
+func (s BoardService) GetLabels(context models.Context, boardId string) ([]*models.Label, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, boardId, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.GetLabels(context, boardId)
+}
+
+func (s BoardService) CreateLabel(context models.Context, label *models.Label) (string, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, label.BoardID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return s.repo.CreateLabel(context, label)
+}
+
+func (s BoardService) UpdateLabel(context models.Context, label *models.Label) (int, error) {
+	severity, err := s.memberService.CheckLabelRole(context, label.ID, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.UpdateLabel(context, label)
+}
+
+func (s BoardService) DeleteLabel(context models.Context, id string) (int, error) {
+	severity, err := s.memberService.CheckLabelRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.DeleteLabel(context, id)
+}
+
+func (s BoardService) GetCustomFields(context models.Context, boardId string) ([]*models.CustomField, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, boardId, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.GetCustomFields(context, boardId)
+}
+
+func (s BoardService) CreateCustomField(context models.Context, field *models.CustomField) (string, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, field.BoardID, models.BoardRoleOwner)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return s.repo.CreateCustomField(context, field)
+}
+
+func (s BoardService) UpdateCustomField(context models.Context, field *models.CustomField) (int, error) {
+	severity, err := s.memberService.CheckCustomFieldRole(context, field.ID, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.UpdateCustomField(context, field)
+}
+
+func (s BoardService) DeleteCustomField(context models.Context, id string) (int, error) {
+	severity, err := s.memberService.CheckCustomFieldRole(context, id, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.DeleteCustomField(context, id)
+}
+
+func (s BoardService) GetRules(context models.Context, boardId string) ([]*models.Rule, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, boardId, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.GetRules(context, boardId)
+}
+
+func (s BoardService) CreateRule(context models.Context, rule *models.Rule) (string, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, rule.BoardID, models.BoardRoleOwner)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return s.repo.CreateRule(context, rule)
+}
+
+func (s BoardService) UpdateRule(context models.Context, rule *models.Rule) (int, error) {
+	severity, err := s.memberService.CheckRuleRole(context, rule.ID, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.UpdateRule(context, rule)
+}
+
+func (s BoardService) DeleteRule(context models.Context, id string) (int, error) {
+	severity, err := s.memberService.CheckRuleRole(context, id, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.DeleteRule(context, id)
 }
@@ -3,51 +3,98 @@ package card
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+	"trellode-go/internal/automation"
 	"trellode-go/internal/log"
 	"trellode-go/internal/models"
+	"trellode-go/internal/utils/clone"
+	"trellode-go/internal/utils/commentcount"
+	"trellode-go/internal/utils/imaging"
+	"trellode-go/internal/utils/mention"
 	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/rank"
+	"trellode-go/internal/utils/reaction"
+	recurrenceRule "trellode-go/internal/utils/recurrence"
+	"trellode-go/internal/utils/storage"
+	"trellode-go/internal/utils/wip"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// coverWidth is the width in pixels of the thumbnails of image covers
+const coverWidth = 320
+
 type CardRepository struct {
 	db         *gorm.DB
 	log        *zap.Logger
 	logService log.LogService
+	storage    storage.Storage
+	automation automation.Engine
 }
 
 type CardRepositoryInterface interface {
 	GetCard(models.Context, string) (*models.Card, int, error)
-	CreateCard(models.Context, *models.Card) (string, int, error)
-	UpdateCard(models.Context, *models.Card) (int, error)
+	CreateCard(models.Context, *models.Card) (string, string, int, error)
+	UpdateCard(models.Context, *models.Card) (string, int, error)
 	DeleteCard(models.Context, string) (int, error)
+	CopyCard(models.Context, string, string, string, bool) (string, string, int, error)
+	AddLabel(models.Context, string, string) (int, error)
+	RemoveLabel(models.Context, string, string) (int, error)
+	AssignUser(models.Context, string, string) (int, error)
+	UnassignUser(models.Context, string, string) (int, error)
+	AddLink(models.Context, *models.CardLink) (string, int, error)
+	RemoveLink(models.Context, string, string) (int, error)
+	SetCover(models.Context, string, *models.CardCover) (int, error)
+	SetRecurrence(models.Context, string, *models.CardRecurrence) (int, error)
+	ToggleReaction(models.Context, string, string) ([]models.ReactionSummary, int, error)
 }
 
-func NewCardRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) CardRepository {
+func NewCardRepository(db *gorm.DB, log *zap.Logger, logService log.LogService, storage storage.Storage) CardRepository {
 	return CardRepository{
 		db:         db,
 		log:        log,
 		logService: logService,
+		storage:    storage,
+		automation: automation.NewEngine(logService, log),
 	}
 }
 
 func (repo CardRepository) GetCard(context models.Context, id string) (*models.Card, int, error) {
 	var card *models.Card
 	err := repo.db.
-		Preload("Comments", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC")
-		}).
 		Preload("Checklists", func(db *gorm.DB) *gorm.DB {
 			return db.Where("archived_at IS NULL").Order("title ASC")
 		}).
 		Preload("Checklists.Items", func(db *gorm.DB) *gorm.DB {
-			return db.Order("position ASC")
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Labels", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name ASC")
+		}).
+		Preload("Assignees", func(db *gorm.DB) *gorm.DB {
+			return db.Order("firstname ASC, lastname ASC")
+		}).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		}).
+		Preload("Links", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
 		}).
+		Preload("Links.LinkedCard").
+		Preload("LinkedFrom", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("LinkedFrom.Card").
+		Preload("CustomFieldValues").
+		Preload("CustomFieldValues.CustomField").
+		Preload("Recurrence").
 		Where("id = ?", id).
 		First(&card).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -56,44 +103,71 @@ func (repo CardRepository) GetCard(context models.Context, id string) (*models.C
 	if card.ID == "" {
 		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
 	}
+	for i := range card.Checklists {
+		models.NumberChecklistItems(card.Checklists[i].Items)
+	}
+
+	// comments are loaded page by page, the card only gives how many there are
+	commentCountOfCard, err := commentcount.Load(repo.db, "?", card.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	card.CommentCount = commentCountOfCard[card.ID]
+	summaries, err := reaction.Load(repo.db, models.ReactionTargetCard, []string{card.ID}, context.UserId)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	card.Reactions = summaries[card.ID]
 
 	return card, http.StatusOK, nil
 }
 
-func (repo CardRepository) CreateCard(context models.Context, card *models.Card) (string, int, error) {
-	// get cards of list to determine position of new card
+func (repo CardRepository) CreateCard(context models.Context, card *models.Card) (string, string, int, error) {
 	var list *models.List
 	err := repo.db.
-		Preload("Cards", repo.db.Where("archived_at IS NULL")).
 		Where("id = ?", card.ListID).
 		First(&list).Error
 	if err != nil {
-		return "", http.StatusInternalServerError, err
+		return "", "", http.StatusInternalServerError, err
 	}
 	if list.ID == "" {
-		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+		return "", "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+	}
+	// the new card goes at the end of the list
+	lastRank, err := lastCardRank(repo.db, list.ID)
+	if err != nil {
+		return "", "", http.StatusInternalServerError, err
+	}
+
+	severity, err := checkDates(context, card)
+	if err != nil {
+		return "", "", severity, err
+	}
+	warning, severity, err := wip.Check(context, repo.db, list, "")
+	if err != nil {
+		return "", "", severity, err
 	}
 
 	// generate UUID
 	card.ID = uuid.NewString()
 	card.ArchivedAt = nil
-	card.Position = len(list.Cards) + 1
+	card.Rank = rank.Between(lastRank, "")
 
 	tx := repo.db.Begin()
 
-	err = tx.Create(&card).Error
+	err = tx.Omit("Labels", "Assignees", "Attachments", "Links", "LinkedFrom", "CustomFieldValues", "Recurrence").Create(&card).Error
 	if err != nil {
 		tx.Rollback()
-		return "", http.StatusInternalServerError, err
+		return "", "", http.StatusInternalServerError, err
 	}
 
 	// log operation
 	boardId, err := repo.getBoardIdOfCard(card)
 	if boardId == "" || err != nil {
 		tx.Rollback()
-		return "", http.StatusInternalServerError, err
+		return "", "", http.StatusInternalServerError, err
 	}
-	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
 		UserID:         context.UserId,
 		BoardID:        boardId,
 		Action:         "createcard",
@@ -101,22 +175,46 @@ func (repo CardRepository) CreateCard(context models.Context, card *models.Card)
 	})
 	if err != nil {
 		tx.Rollback()
-		return "", severity, err
+		return "", "", severity, err
+	}
+
+	// notify users mentioned in the description
+	err = mention.Notify(tx, card.Description, "", models.Notification{
+		Type:    models.NotificationTypeMention,
+		ActorID: context.UserId,
+		BoardID: boardId,
+		CardID:  card.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", "", http.StatusInternalServerError, err
+	}
+
+	// run the rules of the board
+	err = repo.automation.Run(context, tx, automation.Event{
+		Trigger: models.RuleTriggerCardCreated,
+		BoardID: boardId,
+		CardID:  card.ID,
+		ListID:  card.ListID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", "", http.StatusInternalServerError, err
 	}
 
 	tx.Commit()
 
-	return card.ID, http.StatusCreated, nil
+	return card.ID, warning, http.StatusCreated, nil
 }
 
-func (repo CardRepository) UpdateCard(context models.Context, card *models.Card) (int, error) {
+func (repo CardRepository) UpdateCard(context models.Context, card *models.Card) (string, int, error) {
 	// get card from db
 	cardBefore, severity, err := repo.GetCard(context, card.ID)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return severity, err
+		return "", severity, err
 	}
 	if cardBefore.ID == "" {
-		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
 	}
 
 	card.UpdatedAt = time.Now()
@@ -125,24 +223,63 @@ func (repo CardRepository) UpdateCard(context models.Context, card *models.Card)
 	if card.ArchivedAt != nil && card.ArchivedAt.Format("2006-01-02") == epoch0.Format("2006-01-02") {
 		card.ArchivedAt = nil
 	}
+	severity, err = checkDates(context, card)
+	if err != nil {
+		return "", severity, err
+	}
+	// a restored card enters its list again
+	warning := ""
+	if cardBefore.ArchivedAt != nil && card.ArchivedAt == nil {
+		var list models.List
+		err = repo.db.Where("id = ?", cardBefore.ListID).First(&list).Error
+		if err != nil {
+			return "", http.StatusInternalServerError, err
+		}
+		warning, severity, err = wip.Check(context, repo.db, &list, card.ID)
+		if err != nil {
+			return "", severity, err
+		}
+	}
+
+	// the cover has its own endpoint
+	card.Cover = cardBefore.Cover
+	card.Version = cardBefore.Version + 1
+	// cards are ordered through their own endpoints
+	card.Rank = cardBefore.Rank
+
+	// custom field values are only updated when sent
+	if card.CustomFieldValues == nil {
+		card.CustomFieldValues = cardBefore.CustomFieldValues
+	} else {
+		severity, err = repo.checkCustomFieldValues(context, cardBefore, card)
+		if err != nil {
+			return "", severity, err
+		}
+	}
 
 	// what changed?
 	changes, err := whatChanged(cardBefore, card)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return "", http.StatusInternalServerError, err
 	}
 	// marshal changes to JSON string
 	changesJson, err := json.Marshal(changes)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return "", http.StatusInternalServerError, err
 	}
 
 	tx := repo.db.Begin()
 
-	err = tx.Omit("Comments", "ListID", "CreatedAt").Save(&card).Error
+	err = tx.Omit("Comments", "Checklists", "Labels", "Assignees", "Attachments", "Links", "LinkedFrom", "CustomFieldValues", "Recurrence", "ListID", "CreatedAt").Save(&card).Error
 	if err != nil {
 		tx.Rollback()
-		return http.StatusInternalServerError, err
+		return "", http.StatusInternalServerError, err
+	}
+
+	err = saveCustomFieldValues(tx, cardBefore, card)
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
 	}
 
 	// log operation
@@ -153,10 +290,16 @@ func (repo CardRepository) UpdateCard(context models.Context, card *models.Card)
 	if cardBefore.ArchivedAt != nil && card.ArchivedAt == nil {
 		operation = "restorecard"
 	}
+	if cardBefore.CompletedAt == nil && card.CompletedAt != nil {
+		operation = "completecard"
+	}
+	if cardBefore.CompletedAt != nil && card.CompletedAt == nil {
+		operation = "reopencard"
+	}
 	boardId, err := repo.getBoardIdOfCard(card)
 	if boardId == "" || err != nil {
 		tx.Rollback()
-		return http.StatusInternalServerError, err
+		return "", http.StatusInternalServerError, err
 	}
 	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
 		UserID:         context.UserId,
@@ -167,12 +310,24 @@ func (repo CardRepository) UpdateCard(context models.Context, card *models.Card)
 	})
 	if err != nil {
 		tx.Rollback()
-		return severity, err
+		return "", severity, err
+	}
+
+	// notify users mentioned by the update of the description
+	err = mention.Notify(tx, card.Description, cardBefore.Description, models.Notification{
+		Type:    models.NotificationTypeMention,
+		ActorID: context.UserId,
+		BoardID: boardId,
+		CardID:  card.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
 	}
 
 	tx.Commit()
 
-	return http.StatusAccepted, nil
+	return warning, http.StatusAccepted, nil
 }
 
 func (repo CardRepository) DeleteCard(context models.Context, id string) (int, error) {
@@ -186,47 +341,69 @@ func (repo CardRepository) DeleteCard(context models.Context, id string) (int, e
 
 	tx := repo.db.Begin()
 
-	// remove comments
-	for _, comment := range card.Comments {
-		err = tx.Delete(&comment).Error
-		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
-		}
+	// remove reactions on the card and its comments, then comments with their replies
+	err = reaction.DeleteOfCards(tx, "?", card.ID)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
 	}
-	// remove card
-	err = tx.Delete(&card).Error
+	err = tx.Where("comment_id IN (SELECT id FROM comments WHERE card_id = ?)", card.ID).Delete(&models.CommentRevision{}).Error
 	if err != nil {
 		tx.Rollback()
 		return http.StatusInternalServerError, err
 	}
-
-	// get list from db to update positions
-	listId := card.ListID
-	var list models.List
-	err = tx.Where("id = ?", listId).First(&list).Error
+	err = tx.Where("card_id = ?", card.ID).Delete(&models.Comment{}).Error
 	if err != nil {
 		tx.Rollback()
 		return http.StatusInternalServerError, err
 	}
-	if list.ID == "" {
+	// remove custom field values
+	err = tx.Where("card_id = ?", card.ID).Delete(&models.CustomFieldValue{}).Error
+	if err != nil {
 		tx.Rollback()
-		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+		return http.StatusInternalServerError, err
 	}
-
-	// update positions of cards
-	newPosition := 0
-	for _, loopCard := range list.Cards {
-		// don't process removed record
-		if loopCard.ID == id {
-			continue
-		}
-		newPosition++
-		err := tx.Model(&models.Card{}).Where("id = ?", loopCard.ID).Update("position", newPosition).Error
-		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
-		}
+	// remove labels
+	err = tx.Where("card_id = ?", card.ID).Delete(&models.CardLabel{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove assignees
+	err = tx.Where("card_id = ?", card.ID).Delete(&models.CardAssignee{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove links from and to the card
+	err = tx.Where("card_id = ? OR linked_card_id = ?", card.ID, card.ID).Delete(&models.CardLink{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove recurrence
+	err = tx.Where("card_id = ?", card.ID).Delete(&models.CardRecurrence{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove notifications
+	err = tx.Where("card_id = ?", card.ID).Delete(&models.Notification{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove attachments, their data is removed once the card is gone
+	err = tx.Where("card_id = ?", card.ID).Delete(&models.Attachment{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// remove card
+	err = tx.Delete(&card).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
 	}
 
 	// log operation
@@ -248,52 +425,938 @@ func (repo CardRepository) DeleteCard(context models.Context, id string) (int, e
 
 	tx.Commit()
 
+	for _, attachment := range card.Attachments {
+		err = repo.storage.Delete(attachment.StorageKey)
+		if err != nil {
+			repo.log.Warn("Failed to delete attachment data " + attachment.StorageKey + ": " + err.Error())
+		}
+	}
+
 	return http.StatusAccepted, nil
 }
 
-func (repo CardRepository) getBoardIdOfCard(card *models.Card) (string, error) {
-	var list *models.List
-	err := repo.db.
-		Where("id = ?", card.ListID).
-		Find(&list).Error
+// CopyCard deep-copies a card (checklists with items and optionally comments) at the end of the target list
+func (repo CardRepository) CopyCard(context models.Context, id string, targetListId string, title string, withComments bool) (string, string, int, error) {
+	source, severity, err := repo.GetCard(context, id)
 	if err != nil {
-		return "", err
+		return "", "", severity, err
+	}
+	if withComments {
+		err = repo.db.Where("card_id = ?", source.ID).Order("created_at DESC").Find(&source.Comments).Error
+		if err != nil {
+			return "", "", http.StatusInternalServerError, err
+		}
 	}
 
-	return list.BoardID, nil
+	if targetListId == "" {
+		targetListId = source.ListID
+	}
+	var list models.List
+	err = repo.db.
+		Where("id = ?", targetListId).
+		First(&list).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", "", http.StatusInternalServerError, err
+	}
+	if list.ID == "" {
+		return "", "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+	}
+	warning, severity, err := wip.Check(context, repo.db, &list, "")
+	if err != nil {
+		return "", "", severity, err
+	}
+	// the copy goes at the end of the target list
+	lastRank, err := lastCardRank(repo.db, list.ID)
+	if err != nil {
+		return "", "", http.StatusInternalServerError, err
+	}
+
+	if title != "" {
+		source.Title = title
+	}
+
+	tx := repo.db.Begin()
+
+	// labels and custom fields are kept on the same board, matched by name on another board
+	sourceBoardId, err := repo.getBoardIdOfCard(source)
+	if err != nil {
+		tx.Rollback()
+		return "", "", http.StatusInternalServerError, err
+	}
+	mapping, err := clone.Match(tx, sourceBoardId, list.BoardID)
+	if err != nil {
+		tx.Rollback()
+		return "", "", http.StatusInternalServerError, err
+	}
+	card, err := clone.Card(tx, source, list.ID, rank.Between(lastRank, ""), withComments, mapping)
+	if err != nil {
+		tx.Rollback()
+		return "", "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "copiedfrom",
+		FromValue: source.ID,
+		ToValue:   card.ID,
+	}})
+	if err != nil {
+		tx.Rollback()
+		return "", "", http.StatusInternalServerError, err
+	}
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        list.BoardID,
+		Action:         "copycard",
+		ActionTargetID: card.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", "", severity, err
+	}
+
+	tx.Commit()
+
+	return card.ID, warning, http.StatusCreated, nil
 }
 
-// whatChanged calculates the changes between two Card models and returns an array of LogChange models.
-//
-// Parameters:
-// - cardBefore: a pointer to the previous Card model.
-// - cardAfter: a pointer to the updated Card model.
-//
-// Returns:
-// - []*models.LogChange: an array of LogChange models representing the changes between the two Card models.
-// - error: an error if any occurred during the calculation.
-func whatChanged(cardBefore *models.Card, cardAfter *models.Card) ([]*models.LogChange, error) {
-	changes := []*models.LogChange{}
+// AddLabel sets a label of the card board on the card
+func (repo CardRepository) AddLabel(context models.Context, cardId string, labelId string) (int, error) {
+	return repo.setLabel(context, cardId, labelId, true)
+}
 
-	if cardBefore.Title != cardAfter.Title {
-		changes = append(changes, &models.LogChange{
-			Field:     "title",
-			FromValue: cardBefore.Title,
-			ToValue:   cardAfter.Title,
-		})
+// RemoveLabel removes a label from the card
+func (repo CardRepository) RemoveLabel(context models.Context, cardId string, labelId string) (int, error) {
+	return repo.setLabel(context, cardId, labelId, false)
+}
+
+func (repo CardRepository) setLabel(context models.Context, cardId string, labelId string, set bool) (int, error) {
+	card, severity, err := repo.GetCard(context, cardId)
+	if err != nil {
+		return severity, err
 	}
-	if cardBefore.Description != cardAfter.Description {
-		changes = append(changes, &models.LogChange{
-			Field:     "description",
-			FromValue: cardBefore.Description,
-			ToValue:   cardAfter.Description,
-		})
+	boardId, err := repo.getBoardIdOfCard(card)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	var label models.Label
+	err = repo.db.Where("id = ?", labelId).First(&label).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if label.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "LabelNotFound"))
+	}
+	if label.BoardID != boardId {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "LabelNotOnBoard"))
+	}
+
+	labelsBefore := []string{}
+	labelsAfter := []string{}
+	hasLabel := false
+	for _, cardLabel := range card.Labels {
+		labelsBefore = append(labelsBefore, cardLabel.Name)
+		if cardLabel.ID == labelId {
+			hasLabel = true
+			continue
+		}
+		labelsAfter = append(labelsAfter, cardLabel.Name)
+	}
+	// nothing to do
+	if hasLabel == set {
+		return http.StatusAccepted, nil
+	}
+	if set {
+		labelsAfter = append(labelsAfter, label.Name)
+		sort.Strings(labelsAfter)
+	}
+
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "labels",
+		FromValue: strings.Join(labelsBefore, ", "),
+		ToValue:   strings.Join(labelsAfter, ", "),
+	}})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	if set {
+		err = tx.Create(&models.CardLabel{CardID: cardId, LabelID: labelId}).Error
+	} else {
+		err = tx.Where("card_id = ? AND label_id = ?", cardId, labelId).Delete(&models.CardLabel{}).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "updatecard",
+		ActionTargetID: cardId,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
 	}
-	if cardBefore.Position != cardAfter.Position {
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// AssignUser assigns a user to the card
+func (repo CardRepository) AssignUser(context models.Context, cardId string, userId string) (int, error) {
+	return repo.setAssignee(context, cardId, userId, true)
+}
+
+// UnassignUser removes a user from the assignees of the card
+func (repo CardRepository) UnassignUser(context models.Context, cardId string, userId string) (int, error) {
+	return repo.setAssignee(context, cardId, userId, false)
+}
+
+func (repo CardRepository) setAssignee(context models.Context, cardId string, userId string, set bool) (int, error) {
+	card, severity, err := repo.GetCard(context, cardId)
+	if err != nil {
+		return severity, err
+	}
+	boardId, err := repo.getBoardIdOfCard(card)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	var user models.User
+	err = repo.db.Where("id = ?", userId).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if user.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "UserNotFound"))
+	}
+
+	isAssigned := false
+	for _, assignee := range card.Assignees {
+		if assignee.ID == userId {
+			isAssigned = true
+		}
+	}
+	// nothing to do
+	if isAssigned == set {
+		return http.StatusAccepted, nil
+	}
+
+	operation := "assigncard"
+	change := &models.LogChange{
+		Field:   "assignee",
+		ToValue: user.Firstname + " " + user.Lastname,
+	}
+	if !set {
+		operation = "unassigncard"
+		change = &models.LogChange{
+			Field:     "assignee",
+			FromValue: user.Firstname + " " + user.Lastname,
+		}
+	}
+	changesJson, err := json.Marshal([]*models.LogChange{change})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	if set {
+		err = tx.Create(&models.CardAssignee{CardID: cardId, UserID: userId}).Error
+	} else {
+		err = tx.Where("card_id = ? AND user_id = ?", cardId, userId).Delete(&models.CardAssignee{}).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         operation,
+		ActionTargetID: cardId,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// AddLink links the card link.CardID to the card link.LinkedCardID, which can be on another board.
+// Blocking links cannot form a cycle.
+func (repo CardRepository) AddLink(context models.Context, link *models.CardLink) (string, int, error) {
+	if link.Type != models.CardLinkBlocks && link.Type != models.CardLinkRelatesTo && link.Type != models.CardLinkDuplicates {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidCardLinkType"))
+	}
+	if link.CardID == link.LinkedCardID {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "CardLinkToItself"))
+	}
+
+	card, severity, err := repo.GetCard(context, link.CardID)
+	if err != nil {
+		return "", severity, err
+	}
+	var linkedCard models.Card
+	err = repo.db.Where("id = ?", link.LinkedCardID).First(&linkedCard).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if linkedCard.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
+	}
+	boardId, err := repo.getBoardIdOfCard(card)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	for _, existing := range card.Links {
+		if existing.LinkedCardID == link.LinkedCardID && existing.Type == link.Type {
+			return "", http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "CardLinkExists"))
+		}
+	}
+	if link.Type == models.CardLinkBlocks {
+		// the new link closes a cycle if the linked card already blocks the card, directly or not
+		cycle, err := blocks(repo.db, link.LinkedCardID, link.CardID)
+		if err != nil {
+			return "", http.StatusInternalServerError, err
+		}
+		if cycle {
+			return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "CardLinkCycle"))
+		}
+	}
+
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:   "link",
+		ToValue: link.Type + " " + linkedCard.Title,
+	}})
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	link.ID = uuid.NewString()
+
+	tx := repo.db.Begin()
+
+	err = tx.Omit("Card", "LinkedCard").Create(&link).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "linkcard",
+		ActionTargetID: card.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return link.ID, http.StatusCreated, nil
+}
+
+// RemoveLink removes a link from or to the card
+func (repo CardRepository) RemoveLink(context models.Context, cardId string, linkId string) (int, error) {
+	var link models.CardLink
+	err := repo.db.
+		Preload("Card").
+		Preload("LinkedCard").
+		Where("id = ? AND (card_id = ? OR linked_card_id = ?)", linkId, cardId, cardId).
+		First(&link).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if link.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardLinkNotFound"))
+	}
+
+	// the log goes to the board of the card the link belongs to
+	boardId, err := repo.getBoardIdOfCard(link.Card)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "link",
+		FromValue: link.Type + " " + link.LinkedCard.Title,
+	}})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Delete(&models.CardLink{}, "id = ?", link.ID).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "unlinkcard",
+		ActionTargetID: link.CardID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// blocks tells whether the card fromCardId blocks the card toCardId, directly or through other cards
+func blocks(db *gorm.DB, fromCardId string, toCardId string) (bool, error) {
+	visited := map[string]bool{fromCardId: true}
+	frontier := []string{fromCardId}
+	for len(frontier) > 0 {
+		blocked := []string{}
+		err := db.Model(&models.CardLink{}).
+			Where("type = ? AND card_id IN ?", models.CardLinkBlocks, frontier).
+			Pluck("linked_card_id", &blocked).Error
+		if err != nil {
+			return false, err
+		}
+		frontier = []string{}
+		for _, cardId := range blocked {
+			if cardId == toCardId {
+				return true, nil
+			}
+			if !visited[cardId] {
+				visited[cardId] = true
+				frontier = append(frontier, cardId)
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// SetCover sets the cover of a card to a solid color or to an image attachment of the card,
+// the cover is removed when neither is given. cover is updated with the resulting cover.
+func (repo CardRepository) SetCover(context models.Context, cardId string, cover *models.CardCover) (int, error) {
+	card, severity, err := repo.GetCard(context, cardId)
+	if err != nil {
+		return severity, err
+	}
+	boardId, err := repo.getBoardIdOfCard(card)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	newCover := models.CardCover{}
+	if cover.AttachmentID != nil && *cover.AttachmentID != "" {
+		var attachment *models.Attachment
+		for i := range card.Attachments {
+			if card.Attachments[i].ID == *cover.AttachmentID {
+				attachment = &card.Attachments[i]
+			}
+		}
+		if attachment == nil {
+			return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "AttachmentNotFound"))
+		}
+		if !strings.HasPrefix(attachment.ContentType, "image/") {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "AttachmentNotAnImage"))
+		}
+		data, err := repo.storage.Get(attachment.StorageKey)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		imageData, err := io.ReadAll(data)
+		data.Close()
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		thumbnail, averageColor, err := imaging.Thumbnail(imageData, coverWidth)
+		if err != nil {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "AttachmentNotAnImage"))
+		}
+		dataUrl, err := imaging.JPEGDataURL(thumbnail)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		newCover = models.CardCover{
+			Color:        imaging.ToCSS(averageColor),
+			AttachmentID: &attachment.ID,
+			Thumbnail:    dataUrl,
+			TextColor:    imaging.ContrastColor(averageColor),
+		}
+	} else if cover.Color != "" {
+		color, err := imaging.ParseHexColor(cover.Color)
+		if err != nil {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidColor"))
+		}
+		newCover = models.CardCover{
+			Color:     imaging.ToCSS(color),
+			TextColor: imaging.ContrastColor(color),
+		}
+	}
+
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "cover",
+		FromValue: coverDescription(card, card.Cover),
+		ToValue:   coverDescription(card, newCover),
+	}})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.Card{}).
+		Where("id = ?", cardId).
+		Select("cover_color", "cover_attachment_id", "cover_thumbnail", "cover_text_color").
+		Updates(&models.Card{Cover: newCover}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "updatecard",
+		ActionTargetID: cardId,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	*cover = newCover
+
+	return http.StatusAccepted, nil
+}
+
+// coverDescription describes a cover of a card for the activity log: the attachment name for image covers, the color otherwise
+func coverDescription(card *models.Card, cover models.CardCover) string {
+	if cover.AttachmentID != nil {
+		for _, attachment := range card.Attachments {
+			if attachment.ID == *cover.AttachmentID {
+				return attachment.Name
+			}
+		}
+	}
+	return cover.Color
+}
+
+// ToggleReaction puts an emoji of the current user on the card, or removes it when already put, and returns the reactions on the card
+func (repo CardRepository) ToggleReaction(context models.Context, cardId string, emoji string) ([]models.ReactionSummary, int, error) {
+	card, severity, err := repo.GetCard(context, cardId)
+	if err != nil {
+		return nil, severity, err
+	}
+	if !reaction.IsEmoji(emoji) {
+		return nil, http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidEmoji"))
+	}
+
+	tx := repo.db.Begin()
+
+	put, err := reaction.Toggle(tx, models.ReactionTargetCard, cardId, context.UserId, emoji)
+	if err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// log operation
+	change := &models.LogChange{Field: "reaction", FromValue: emoji}
+	if put {
+		change = &models.LogChange{Field: "reaction", ToValue: emoji}
+	}
+	changesJson, err := json.Marshal([]*models.LogChange{change})
+	if err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+	boardId, err := repo.getBoardIdOfCard(card)
+	if err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "reactcard",
+		ActionTargetID: cardId,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, severity, err
+	}
+
+	tx.Commit()
+
+	summaries, err := reaction.Load(repo.db, models.ReactionTargetCard, []string{cardId}, context.UserId)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return summaries[cardId], http.StatusOK, nil
+}
+
+// SetRecurrence makes the card recur following the rule of recurrence, the recurrence is removed when no rule is given.
+// recurrence is updated with the resulting recurrence.
+func (repo CardRepository) SetRecurrence(context models.Context, cardId string, recurrence *models.CardRecurrence) (int, error) {
+	card, severity, err := repo.GetCard(context, cardId)
+	if err != nil {
+		return severity, err
+	}
+	boardId, err := repo.getBoardIdOfCard(card)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if recurrence.Rule != "" {
+		rule, err := recurrenceRule.Parse(recurrence.Rule)
+		if err != nil || recurrence.DueAfterSeconds < 0 {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidRecurrence"))
+		}
+		if recurrence.ListID == "" {
+			recurrence.ListID = card.ListID
+		}
+		var list models.List
+		err = repo.db.Where("id = ?", recurrence.ListID).First(&list).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusInternalServerError, err
+		}
+		if list.ID == "" {
+			return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+		}
+		if list.BoardID != boardId {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "RecurrenceListNotOnBoard"))
+		}
+
+		now := time.Now()
+		if recurrence.StartAt.IsZero() {
+			recurrence.StartAt = now
+		}
+		recurrence.CardID = cardId
+		recurrence.Rule = rule.String()
+		recurrence.NextAt = rule.Next(recurrence.StartAt, now)
+		recurrence.CreatedAt = now
+		recurrence.UpdatedAt = now
+		if recurrence.NextAt.IsZero() {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidRecurrence"))
+		}
+	}
+
+	change := &models.LogChange{
+		Field:   "recurrence",
+		ToValue: recurrence.Rule,
+	}
+	if card.Recurrence != nil {
+		change.FromValue = card.Recurrence.Rule
+	}
+	changesJson, err := json.Marshal([]*models.LogChange{change})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Where("card_id = ?", cardId).Delete(&models.CardRecurrence{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	if recurrence.Rule != "" {
+		err = tx.Create(recurrence).Error
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "updatecard",
+		ActionTargetID: cardId,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// checkCustomFieldValues validates and normalizes the custom field values sent for a card against the fields of its board.
+// Values without content are left out, which removes them from the card.
+func (repo CardRepository) checkCustomFieldValues(context models.Context, cardBefore *models.Card, card *models.Card) (int, error) {
+	boardId, err := repo.getBoardIdOfCard(cardBefore)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	fields := []*models.CustomField{}
+	err = repo.db.Where("board_id = ?", boardId).Find(&fields).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	fieldsById := map[string]*models.CustomField{}
+	for _, field := range fields {
+		fieldsById[field.ID] = field
+	}
+
+	values := []models.CustomFieldValue{}
+	for _, value := range card.CustomFieldValues {
+		field, ok := fieldsById[value.CustomFieldID]
+		if !ok {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "CustomFieldNotOnBoard"))
+		}
+		if value.Value == "" {
+			continue
+		}
+		normalized, ok := normalizeCustomFieldValue(field, value.Value)
+		if !ok {
+			return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidCustomFieldValue") + ": " + field.Name)
+		}
+		values = append(values, models.CustomFieldValue{
+			CardID:        card.ID,
+			CustomFieldID: field.ID,
+			CustomField:   field,
+			Value:         normalized,
+		})
+	}
+	card.CustomFieldValues = values
+
+	return http.StatusOK, nil
+}
+
+// saveCustomFieldValues stores the custom field values of cardAfter, removing the ones it no longer has
+func saveCustomFieldValues(tx *gorm.DB, cardBefore *models.Card, cardAfter *models.Card) error {
+	valuesBefore := map[string]string{}
+	for _, value := range cardBefore.CustomFieldValues {
+		valuesBefore[value.CustomFieldID] = value.Value
+	}
+	valuesAfter := map[string]string{}
+	for _, value := range cardAfter.CustomFieldValues {
+		valuesAfter[value.CustomFieldID] = value.Value
+	}
+
+	for fieldId := range valuesBefore {
+		if _, ok := valuesAfter[fieldId]; !ok {
+			err := tx.Where("card_id = ? AND custom_field_id = ?", cardAfter.ID, fieldId).Delete(&models.CustomFieldValue{}).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for fieldId, value := range valuesAfter {
+		before, ok := valuesBefore[fieldId]
+		if !ok {
+			err := tx.Create(&models.CustomFieldValue{CardID: cardAfter.ID, CustomFieldID: fieldId, Value: value}).Error
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if before != value {
+			err := tx.Model(&models.CustomFieldValue{}).
+				Where("card_id = ? AND custom_field_id = ?", cardAfter.ID, fieldId).
+				Updates(map[string]interface{}{"value": value, "updated_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeCustomFieldValue checks that value fits the type of field and returns it in its stored form
+func normalizeCustomFieldValue(field *models.CustomField, value string) (string, bool) {
+	switch field.Type {
+	case models.CustomFieldTypeText:
+		return value, true
+	case models.CustomFieldTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), true
+	case models.CustomFieldTypeDate:
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", false
+		}
+		return date.Format("2006-01-02"), true
+	case models.CustomFieldTypeCheckbox:
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatBool(checked), true
+	case models.CustomFieldTypeDropdown:
+		for _, option := range field.Options {
+			if option == value {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// checkDates returns an error if the card starts after its due date
+func checkDates(context models.Context, card *models.Card) (int, error) {
+	if card.StartAt != nil && card.DueAt != nil && card.StartAt.After(*card.DueAt) {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "StartAfterDue"))
+	}
+
+	return http.StatusOK, nil
+}
+
+// formatDate formats an optional date for the activity log, to the second as stored in db
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// lastCardRank returns the highest rank of the cards of a list, archived ones included so that they can be restored
+// at their place
+func lastCardRank(db *gorm.DB, listId string) (string, error) {
+	var lastRank *string
+	err := db.Model(&models.Card{}).Where("list_id = ?", listId).Select("MAX(rank_key)").Row().Scan(&lastRank)
+	if err != nil || lastRank == nil {
+		return "", err
+	}
+
+	return *lastRank, nil
+}
+
+func (repo CardRepository) getBoardIdOfCard(card *models.Card) (string, error) {
+	var list *models.List
+	err := repo.db.
+		Where("id = ?", card.ListID).
+		Find(&list).Error
+	if err != nil {
+		return "", err
+	}
+
+	return list.BoardID, nil
+}
+
+// whatChanged calculates the changes between two Card models and returns an array of LogChange models.
+//
+// Parameters:
+// - cardBefore: a pointer to the previous Card model.
+// - cardAfter: a pointer to the updated Card model.
+//
+// Returns:
+// - []*models.LogChange: an array of LogChange models representing the changes between the two Card models.
+// - error: an error if any occurred during the calculation.
+func whatChanged(cardBefore *models.Card, cardAfter *models.Card) ([]*models.LogChange, error) {
+	changes := []*models.LogChange{}
+
+	if cardBefore.Title != cardAfter.Title {
+		changes = append(changes, &models.LogChange{
+			Field:     "title",
+			FromValue: cardBefore.Title,
+			ToValue:   cardAfter.Title,
+		})
+	}
+	if cardBefore.Description != cardAfter.Description {
+		changes = append(changes, &models.LogChange{
+			Field:     "description",
+			FromValue: cardBefore.Description,
+			ToValue:   cardAfter.Description,
+		})
+	}
+
+	if formatDate(cardBefore.StartAt) != formatDate(cardAfter.StartAt) {
+		changes = append(changes, &models.LogChange{
+			Field:     "startAt",
+			FromValue: formatDate(cardBefore.StartAt),
+			ToValue:   formatDate(cardAfter.StartAt),
+		})
+	}
+	if formatDate(cardBefore.DueAt) != formatDate(cardAfter.DueAt) {
+		changes = append(changes, &models.LogChange{
+			Field:     "dueAt",
+			FromValue: formatDate(cardBefore.DueAt),
+			ToValue:   formatDate(cardAfter.DueAt),
+		})
+	}
+	if formatDate(cardBefore.CompletedAt) != formatDate(cardAfter.CompletedAt) {
+		changes = append(changes, &models.LogChange{
+			Field:     "completedAt",
+			FromValue: formatDate(cardBefore.CompletedAt),
+			ToValue:   formatDate(cardAfter.CompletedAt),
+		})
+	}
+
+	// custom fields are logged by name
+	valuesBefore := map[string]models.CustomFieldValue{}
+	for _, value := range cardBefore.CustomFieldValues {
+		valuesBefore[value.CustomFieldID] = value
+	}
+	valuesAfter := map[string]models.CustomFieldValue{}
+	for _, value := range cardAfter.CustomFieldValues {
+		valuesAfter[value.CustomFieldID] = value
+	}
+	fieldIds := []string{}
+	for fieldId := range valuesBefore {
+		fieldIds = append(fieldIds, fieldId)
+	}
+	for fieldId := range valuesAfter {
+		if _, ok := valuesBefore[fieldId]; !ok {
+			fieldIds = append(fieldIds, fieldId)
+		}
+	}
+	sort.Strings(fieldIds)
+	for _, fieldId := range fieldIds {
+		before, after := valuesBefore[fieldId], valuesAfter[fieldId]
+		if before.Value == after.Value {
+			continue
+		}
+		name := fieldId
+		if before.CustomField != nil {
+			name = before.CustomField.Name
+		} else if after.CustomField != nil {
+			name = after.CustomField.Name
+		}
 		changes = append(changes, &models.LogChange{
-			Field:     "position",
-			FromValue: strconv.Itoa(cardBefore.Position),
-			ToValue:   strconv.Itoa(cardAfter.Position),
+			Field:     "customfield:" + name,
+			FromValue: before.Value,
+			ToValue:   after.Value,
 		})
 	}
 
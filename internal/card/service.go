@@ -1,37 +1,193 @@
 package card
 
-import "trellode-go/internal/models"
+import (
+	"errors"
+	"net/http"
+	"trellode-go/internal/member"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+)
 
 type CardServiceInterface interface {
 	GetCard(models.Context, string) (*models.Card, int, error)
-	CreateCard(models.Context, *models.Card) (string, int, error)
-	UpdateCard(models.Context, *models.Card) (int, error)
+	CreateCard(models.Context, *models.Card) (string, string, int, error)
+	UpdateCard(models.Context, *models.Card) (string, int, error)
 	DeleteCard(models.Context, string) (int, error)
+	CopyCard(models.Context, string, string, string, bool) (string, string, int, error)
+	AddLabel(models.Context, string, string) (int, error)
+	RemoveLabel(models.Context, string, string) (int, error)
+	AssignUser(models.Context, string, string) (int, error)
+	UnassignUser(models.Context, string, string) (int, error)
+	AddLink(models.Context, *models.CardLink) (string, int, error)
+	RemoveLink(models.Context, string, string) (int, error)
+	SetCover(models.Context, string, *models.CardCover) (int, error)
+	SetRecurrence(models.Context, string, *models.CardRecurrence) (int, error)
+	ToggleReaction(models.Context, string, string) ([]models.ReactionSummary, int, error)
 }
 
 type CardService struct {
-	repo CardRepositoryInterface
+	repo          CardRepositoryInterface
+	memberService member.MemberService
 }
 
 // NewPersonService returns a service to manipulate unit
-func NewCardService(repo CardRepositoryInterface) CardService {
+func NewCardService(repo CardRepositoryInterface, memberService member.MemberService) CardService {
 	return CardService{
-		repo: repo,
+		repo:          repo,
+		memberService: memberService,
 	}
 }
 
 func (p CardService) GetCard(context models.Context, id string) (*models.Card, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
 	return p.repo.GetCard(context, id)
 }
 
-func (p CardService) CreateCard(context models.Context, board *models.Card) (string, int, error) {
+func (p CardService) CreateCard(context models.Context, board *models.Card) (string, string, int, error) {
+	severity, err := p.memberService.CheckListRole(context, board.ListID, models.BoardRoleEditor)
+	if err != nil {
+		return "", "", severity, err
+	}
+
 	return p.repo.CreateCard(context, board)
 }
 
-func (p CardService) UpdateCard(context models.Context, board *models.Card) (int, error) {
+func (p CardService) UpdateCard(context models.Context, board *models.Card) (string, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, board.ID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
 	return p.repo.UpdateCard(context, board)
 }
 
 func (p CardService) DeleteCard(context models.Context, id string) (int, error) {
+	severity, err := p.memberService.CheckCardRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.DeleteCard(context, id)
 }
+
+func (p CardService) CopyCard(context models.Context, id string, targetListId string, title string, withComments bool) (string, string, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return "", "", severity, err
+	}
+	if targetListId != "" {
+		severity, err = p.memberService.CheckListRole(context, targetListId, models.BoardRoleEditor)
+	} else {
+		severity, err = p.memberService.CheckCardRole(context, id, models.BoardRoleEditor)
+	}
+	if err != nil {
+		return "", "", severity, err
+	}
+
+	return p.repo.CopyCard(context, id, targetListId, title, withComments)
+}
+
+func (p CardService) AddLabel(context models.Context, cardId string, labelId string) (int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.AddLabel(context, cardId, labelId)
+}
+
+func (p CardService) RemoveLabel(context models.Context, cardId string, labelId string) (int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.RemoveLabel(context, cardId, labelId)
+}
+
+func (p CardService) AssignUser(context models.Context, cardId string, userId string) (int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+	// only users having access to the board can be assigned
+	assigneeContext := context
+	assigneeContext.UserId = userId
+	severity, err = p.memberService.CheckCardRole(assigneeContext, cardId, models.BoardRoleViewer)
+	if severity == http.StatusForbidden {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "AssigneeNotMember"))
+	}
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.AssignUser(context, cardId, userId)
+}
+
+func (p CardService) UnassignUser(context models.Context, cardId string, userId string) (int, error) {
+	// any member can unassign himself, only editors can unassign someone else
+	minimumRole := models.BoardRoleEditor
+	if userId == context.UserId {
+		minimumRole = models.BoardRoleViewer
+	}
+	severity, err := p.memberService.CheckCardRole(context, cardId, minimumRole)
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.UnassignUser(context, cardId, userId)
+}
+
+func (p CardService) AddLink(context models.Context, link *models.CardLink) (string, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, link.CardID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+	// the linked card can be on any board the user can see
+	severity, err = p.memberService.CheckCardRole(context, link.LinkedCardID, models.BoardRoleViewer)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return p.repo.AddLink(context, link)
+}
+
+func (p CardService) RemoveLink(context models.Context, cardId string, linkId string) (int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.RemoveLink(context, cardId, linkId)
+}
+
+func (p CardService) SetCover(context models.Context, cardId string, cover *models.CardCover) (int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.SetCover(context, cardId, cover)
+}
+
+func (p CardService) SetRecurrence(context models.Context, cardId string, recurrence *models.CardRecurrence) (int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.SetRecurrence(context, cardId, recurrence)
+}
+
+func (p CardService) ToggleReaction(context models.Context, cardId string, emoji string) ([]models.ReactionSummary, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return p.repo.ToggleReaction(context, cardId, emoji)
+}
@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"strings"
 	"time"
+	"trellode-go/internal/automation"
 	"trellode-go/internal/log"
 	"trellode-go/internal/models"
 	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/rank"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -19,6 +21,7 @@ type ChecklistRepository struct {
 	db         *gorm.DB
 	log        *zap.Logger
 	logService log.LogService
+	automation automation.Engine
 }
 
 type ChecklistRepositoryInterface interface {
@@ -39,6 +42,7 @@ func NewChecklistRepository(db *gorm.DB, log *zap.Logger, logService log.LogServ
 		db:         db,
 		log:        log,
 		logService: logService,
+		automation: automation.NewEngine(logService, log),
 	}
 }
 
@@ -46,7 +50,7 @@ func (repo ChecklistRepository) GetChecklist(context models.Context, id string)
 	var checklist *models.Checklist
 	err := repo.db.
 		Preload("Items", func(db *gorm.DB) *gorm.DB {
-			return db.Order("position ASC")
+			return db.Order("rank_key ASC")
 		}).
 		Where("id = ?", id).
 		First(&checklist).Error
@@ -56,6 +60,7 @@ func (repo ChecklistRepository) GetChecklist(context models.Context, id string)
 	if checklist.ID == "" {
 		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ChecklistNotFound"))
 	}
+	models.NumberChecklistItems(checklist.Items)
 
 	return checklist, http.StatusOK, nil
 }
@@ -105,6 +110,8 @@ func (repo ChecklistRepository) UpdateChecklist(context models.Context, checklis
 	}
 
 	checklist.UpdatedAt = time.Now()
+	// the role was checked on the card of the checklist, which cannot change, nor can its items here
+	checklist.CardID = checklistBefore.CardID
 	// if board.ArchivedAt equals epoch 0, nullify archivedAt
 	epoch0 := time.Unix(0, 0)
 	if checklist.ArchivedAt != nil && checklist.ArchivedAt.Format("2006-01-02") == epoch0.Format("2006-01-02") {
@@ -124,7 +131,7 @@ func (repo ChecklistRepository) UpdateChecklist(context models.Context, checklis
 
 	tx := repo.db.Begin()
 
-	err = tx.Omit("Comments", "ListID", "CreatedAt").Save(&checklist).Error
+	err = tx.Omit("Comments", "Items", "ListID", "CreatedAt").Save(&checklist).Error
 	if err != nil {
 		tx.Rollback()
 		return http.StatusInternalServerError, err
@@ -221,10 +228,8 @@ func (repo ChecklistRepository) GetChecklistItem(context models.Context, id stri
 }
 
 func (repo ChecklistRepository) CreateChecklistItem(context models.Context, checklistItem *models.ChecklistItem) (string, int, error) {
-	// get cards of list to determine position of new card
 	var checklist *models.Checklist
 	err := repo.db.
-		Preload("Items").
 		Where("id = ?", checklistItem.ChecklistID).
 		First(&checklist).Error
 	if err != nil {
@@ -234,8 +239,18 @@ func (repo ChecklistRepository) CreateChecklistItem(context models.Context, chec
 		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ChecklistNotFound"))
 	}
 
+	// the new item goes at the end of the checklist
+	var lastRank *string
+	err = repo.db.Model(&models.ChecklistItem{}).Where("checklist_id = ?", checklist.ID).Select("MAX(rank_key)").Row().Scan(&lastRank)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	if lastRank == nil {
+		lastRank = new(string)
+	}
+
 	checklistItem.ID = uuid.NewString()
-	checklistItem.Position = len(checklist.Items) + 1
+	checklistItem.Rank = rank.Between(*lastRank, "")
 
 	tx := repo.db.Begin()
 
@@ -278,6 +293,9 @@ func (repo ChecklistRepository) UpdateChecklistItem(context models.Context, chec
 	}
 
 	checklistItem.UpdatedAt = time.Now()
+	// items are ordered through their own endpoint and stay in their checklist, on which the role was checked
+	checklistItem.Rank = checklistItemBefore.Rank
+	checklistItem.ChecklistID = checklistItemBefore.ChecklistID
 
 	// what changed?
 	changes, err := whatChangedItem(checklistItemBefore, checklistItem)
@@ -316,6 +334,33 @@ func (repo ChecklistRepository) UpdateChecklistItem(context models.Context, chec
 		return severity, err
 	}
 
+	// checking the last unchecked item completes the checklist, which may trigger rules of the board
+	if checklistItem.Checked && !checklistItemBefore.Checked {
+		var unchecked int64
+		err = tx.Model(&models.ChecklistItem{}).Where("checklist_id = ? AND checked = ?", checklistItemBefore.ChecklistID, false).Count(&unchecked).Error
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+		if unchecked == 0 {
+			var checklist models.Checklist
+			err = tx.Where("id = ?", checklistItemBefore.ChecklistID).First(&checklist).Error
+			if err != nil {
+				tx.Rollback()
+				return http.StatusInternalServerError, err
+			}
+			err = repo.automation.Run(context, tx, automation.Event{
+				Trigger: models.RuleTriggerChecklistCompleted,
+				BoardID: boardId,
+				CardID:  checklist.CardID,
+			})
+			if err != nil {
+				tx.Rollback()
+				return http.StatusInternalServerError, err
+			}
+		}
+	}
+
 	tx.Commit()
 
 	return http.StatusAccepted, nil
@@ -338,33 +383,6 @@ func (repo ChecklistRepository) DeleteChecklistItem(context models.Context, id s
 		return http.StatusInternalServerError, err
 	}
 
-	// get checklist from db to update positions
-	checklistId := checklistItem.ChecklistID
-	checklist, severity, err := repo.GetChecklist(context, checklistId)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		tx.Rollback()
-		return severity, err
-	}
-	if checklist.ID == "" {
-		tx.Rollback()
-		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ChecklistNotFound"))
-	}
-
-	// update positions
-	newPosition := 0
-	for _, item := range checklist.Items {
-		// don't process removed record
-		if item.ID == id {
-			continue
-		}
-		newPosition++
-		err := tx.Model(&models.ChecklistItem{}).Where("id = ?", item.ID).Update("position", newPosition).Error
-		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
-		}
-	}
-
 	// log operation
 	boardId, err := repo.getBoardIdOfChecklistItem(checklistItem)
 	if boardId == "" || err != nil {
@@ -397,22 +415,26 @@ func (repo ChecklistRepository) UpdateChecklistItemsOrder(context models.Context
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ChecklistNotFound"))
 	}
 
-	tx := repo.db.Begin()
-
 	idsOrderedSplit := strings.Split(idsOrdered, ",")
 
-	for i, id := range idsOrderedSplit {
-		var checklistItem *models.ChecklistItem
-		err := tx.Where("id = ?", id).First(&checklistItem).Error
-		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
-		}
-		if checklistItem.ID == "" {
+	itemRanks := map[string]string{}
+	for _, item := range checklist.Items {
+		itemRanks[item.ID] = item.Rank
+	}
+	orderedRanks := []string{}
+	for _, id := range idsOrderedSplit {
+		itemRank, ok := itemRanks[id]
+		if !ok {
 			return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ChecklistItemNotFound"))
 		}
-		checklistItem.Position = i + 1
-		err = tx.Save(&checklistItem).Error
+		orderedRanks = append(orderedRanks, itemRank)
+	}
+
+	tx := repo.db.Begin()
+
+	// only the items out of order get a new rank
+	for i, newRank := range rank.Reorder(orderedRanks) {
+		err = tx.Model(&models.ChecklistItem{}).Where("id = ?", idsOrderedSplit[i]).Update("rank_key", newRank).Error
 		if err != nil {
 			tx.Rollback()
 			return http.StatusInternalServerError, err
@@ -1,6 +1,9 @@
 package checklist
 
-import "trellode-go/internal/models"
+import (
+	"trellode-go/internal/member"
+	"trellode-go/internal/models"
+)
 
 type ChecklistServiceInterface interface {
 	GetChecklist(models.Context, string) (*models.Checklist, int, error)
@@ -16,48 +19,95 @@ type ChecklistServiceInterface interface {
 }
 
 type ChecklistService struct {
-	repo ChecklistRepositoryInterface
+	repo          ChecklistRepositoryInterface
+	memberService member.MemberService
 }
 
 // NewPersonService returns a service to manipulate unit
-func NewChecklistService(repo ChecklistRepositoryInterface) ChecklistService {
+func NewChecklistService(repo ChecklistRepositoryInterface, memberService member.MemberService) ChecklistService {
 	return ChecklistService{
-		repo: repo,
+		repo:          repo,
+		memberService: memberService,
 	}
 }
 
 func (p ChecklistService) GetChecklist(context models.Context, id string) (*models.Checklist, int, error) {
+	severity, err := p.memberService.CheckChecklistRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
 	return p.repo.GetChecklist(context, id)
 }
 
 func (p ChecklistService) CreateChecklist(context models.Context, board *models.Checklist) (string, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, board.CardID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
 	return p.repo.CreateChecklist(context, board)
 }
 
 func (p ChecklistService) UpdateChecklist(context models.Context, board *models.Checklist) (int, error) {
+	severity, err := p.memberService.CheckChecklistRole(context, board.ID, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.UpdateChecklist(context, board)
 }
 
 func (p ChecklistService) DeleteChecklist(context models.Context, id string) (int, error) {
+	severity, err := p.memberService.CheckChecklistRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.DeleteChecklist(context, id)
 }
 
 func (p ChecklistService) GetChecklistItem(context models.Context, id string) (*models.ChecklistItem, int, error) {
+	severity, err := p.memberService.CheckChecklistItemRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
 	return p.repo.GetChecklistItem(context, id)
 }
 
 func (p ChecklistService) CreateChecklistItem(context models.Context, board *models.ChecklistItem) (string, int, error) {
+	severity, err := p.memberService.CheckChecklistRole(context, board.ChecklistID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
 	return p.repo.CreateChecklistItem(context, board)
 }
 
 func (p ChecklistService) UpdateChecklistItem(context models.Context, board *models.ChecklistItem) (int, error) {
+	severity, err := p.memberService.CheckChecklistItemRole(context, board.ID, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.UpdateChecklistItem(context, board)
 }
 
 func (p ChecklistService) DeleteChecklistItem(context models.Context, id string) (int, error) {
+	severity, err := p.memberService.CheckChecklistItemRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.DeleteChecklistItem(context, id)
 }
 
 func (p ChecklistService) UpdateChecklistItemsOrder(context models.Context, checklistId string, idsOrdered string) (int, error) {
+	severity, err := p.memberService.CheckChecklistRole(context, checklistId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.UpdateChecklistItemsOrder(context, checklistId, idsOrdered)
 }
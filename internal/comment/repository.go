@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 	"trellode-go/internal/log"
 	"trellode-go/internal/models"
+	"trellode-go/internal/utils/mention"
 	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/reaction"
+	"trellode-go/internal/utils/tools"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -21,10 +25,12 @@ type CommentRepository struct {
 
 type CommentRepositoryInterface interface {
 	GetComment(models.Context, string) (*models.Comment, int, error)
-	GetComments(models.Context, string) ([]*models.Comment, int, error)
+	GetComments(models.Context, string, int, string) ([]*models.Comment, string, int, error)
 	CreateComment(models.Context, *models.Comment) (string, int, error)
 	UpdateComment(models.Context, *models.Comment) (int, error)
 	DeleteComment(models.Context, string) (int, error)
+	ToggleReaction(models.Context, string, string) ([]models.ReactionSummary, int, error)
+	GetRevisions(models.Context, string) ([]*models.CommentRevision, int, error)
 }
 
 func NewCommentRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) CommentRepository {
@@ -37,33 +43,91 @@ func NewCommentRepository(db *gorm.DB, log *zap.Logger, logService log.LogServic
 
 func (repo CommentRepository) GetComment(context models.Context, id string) (*models.Comment, int, error) {
 	var comment *models.Comment
-	err := repo.db.Where("id = ?", id).First(&comment).Error
+	err := repo.db.
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("id = ?", id).
+		First(&comment).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, http.StatusInternalServerError, err
 	}
 	if comment.ID == "" {
 		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CommentNotFound"))
 	}
+	err = reaction.LoadComments(repo.db, []*models.Comment{comment}, context.UserId)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
 
 	return comment, http.StatusOK, nil
 }
 
-func (repo CommentRepository) GetComments(context models.Context, boardId string) ([]*models.Comment, int, error) {
+// GetComments returns a page of the threads of a card: its top-level comments, most recent first, with their replies.
+// The page starts after cursor (the first page when empty), the cursor of the next page is empty after the last page.
+func (repo CommentRepository) GetComments(context models.Context, cardId string, pageSize int, cursor string) ([]*models.Comment, string, int, error) {
+	pageSize, position, err := tools.CheckCursorPagination(pageSize, cursor)
+	if err != nil {
+		return nil, "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidCursor"))
+	}
+
 	comments := []*models.Comment{}
-	err := repo.db.Where("card_id = ?", boardId).Find(&comments).Error
+	query := repo.db.
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		}).
+		Where("card_id = ? AND parent_id IS NULL", cardId)
+	if position != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", position.CreatedAt, position.CreatedAt, position.ID)
+	}
+	// one more comment tells whether there is a next page
+	err = query.
+		Order("created_at DESC, id DESC").
+		Limit(pageSize + 1).
+		Find(&comments).Error
 	if err != nil {
-		return nil, http.StatusInternalServerError, err
+		return nil, "", http.StatusInternalServerError, err
+	}
+	nextCursor := ""
+	if len(comments) > pageSize {
+		comments = comments[:pageSize]
+		last := comments[len(comments)-1]
+		nextCursor = tools.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.String()
+	}
+
+	err = reaction.LoadComments(repo.db, comments, context.UserId)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, err
 	}
 
-	return comments, http.StatusOK, nil
+	return comments, nextCursor, http.StatusOK, nil
 }
 
+// CreateComment creates a comment, or a reply when ParentID is set: replies to a reply go to the thread of its comment
 func (repo CommentRepository) CreateComment(context models.Context, comment *models.Comment) (string, int, error) {
+	if comment.ParentID != nil && *comment.ParentID == "" {
+		comment.ParentID = nil
+	}
+	if comment.ParentID != nil {
+		var parent models.Comment
+		err := repo.db.Where("id = ?", *comment.ParentID).First(&parent).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", http.StatusInternalServerError, err
+		}
+		if parent.ID == "" || parent.CardID != comment.CardID {
+			return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidCommentParent"))
+		}
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		}
+	}
+
 	tx := repo.db.Begin()
 
 	comment.ID = uuid.NewString()
 	comment.UserID = context.UserId
-	err := tx.Create(&comment).Error
+	comment.Replies = nil
+	err := tx.Omit("Replies").Create(&comment).Error
 	if err != nil {
 		tx.Rollback()
 		return "", http.StatusInternalServerError, err
@@ -86,11 +150,25 @@ func (repo CommentRepository) CreateComment(context models.Context, comment *mod
 		return "", severity, err
 	}
 
+	// notify mentioned users
+	err = mention.Notify(tx, comment.Content, "", models.Notification{
+		Type:      models.NotificationTypeMention,
+		ActorID:   context.UserId,
+		BoardID:   boardId,
+		CardID:    comment.CardID,
+		CommentID: comment.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
 	tx.Commit()
 
 	return comment.ID, http.StatusCreated, nil
 }
 
+// UpdateComment updates the content of a comment, the previous content is kept in the revisions of the comment
 func (repo CommentRepository) UpdateComment(context models.Context, comment *models.Comment) (int, error) {
 	// get comment from db
 	commentBefore, severity, err := repo.GetComment(context, comment.ID)
@@ -100,6 +178,9 @@ func (repo CommentRepository) UpdateComment(context models.Context, comment *mod
 	if commentBefore.ID == "" {
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CommentNotFound"))
 	}
+	if commentBefore.DeletedAt != nil {
+		return http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "CommentDeleted"))
+	}
 
 	// what changed?
 	changes, err := whatChanged(commentBefore, comment)
@@ -112,14 +193,35 @@ func (repo CommentRepository) UpdateComment(context models.Context, comment *mod
 		return http.StatusInternalServerError, err
 	}
 
+	// only the content can change, threads cannot be rearranged
+	comment.CardID = commentBefore.CardID
+	comment.ParentID = commentBefore.ParentID
+	comment.UserID = commentBefore.UserID
+	comment.CreatedAt = commentBefore.CreatedAt
+	comment.UpdatedAt = time.Now()
+	comment.EditedAt = commentBefore.EditedAt
+	comment.DeletedAt = nil
+	edited := comment.Content != commentBefore.Content
+	if edited {
+		comment.EditedAt = &comment.UpdatedAt
+	}
+
 	tx := repo.db.Begin()
 
-	err = tx.Save(&comment).Error
+	err = tx.Omit("Replies").Save(&comment).Error
 	if err != nil {
 		tx.Rollback()
 		return http.StatusInternalServerError, err
 	}
 
+	if edited {
+		err = repo.createRevisions(tx, context, commentBefore, comment)
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+	}
+
 	// log operation
 	boardId, err := repo.getBoardIdOfComment(comment)
 	if boardId == "" || err != nil {
@@ -138,11 +240,25 @@ func (repo CommentRepository) UpdateComment(context models.Context, comment *mod
 		return severity, err
 	}
 
+	// notify users mentioned by the update
+	err = mention.Notify(tx, comment.Content, commentBefore.Content, models.Notification{
+		Type:      models.NotificationTypeMention,
+		ActorID:   context.UserId,
+		BoardID:   boardId,
+		CardID:    commentBefore.CardID,
+		CommentID: comment.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
 	tx.Commit()
 
 	return http.StatusAccepted, nil
 }
 
+// DeleteComment empties a comment, which stays as a placeholder in its thread, and removes its revisions, reactions and notifications
 func (repo CommentRepository) DeleteComment(context models.Context, id string) (int, error) {
 	// get comment from db
 	commentBefore, severity, err := repo.GetComment(context, id)
@@ -152,10 +268,30 @@ func (repo CommentRepository) DeleteComment(context models.Context, id string) (
 	if commentBefore.ID == "" {
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CommentNotFound"))
 	}
+	if commentBefore.DeletedAt != nil {
+		return http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "CommentDeleted"))
+	}
 
 	tx := repo.db.Begin()
 
-	err = tx.Where("id = ?", id).Delete(&models.Comment{}).Error
+	err = tx.Model(&models.Comment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"content": "", "deleted_at": time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("comment_id = ?", id).Delete(&models.CommentRevision{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("target_type = ? AND target_id = ?", models.ReactionTargetComment, id).Delete(&models.Reaction{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("comment_id = ?", id).Delete(&models.Notification{}).Error
 	if err != nil {
 		tx.Rollback()
 		return http.StatusInternalServerError, err
@@ -183,6 +319,110 @@ func (repo CommentRepository) DeleteComment(context models.Context, id string) (
 	return http.StatusAccepted, nil
 }
 
+// ToggleReaction puts an emoji of the current user on the comment, or removes it when already put, and returns the reactions on the comment
+func (repo CommentRepository) ToggleReaction(context models.Context, id string, emoji string) ([]models.ReactionSummary, int, error) {
+	comment, severity, err := repo.GetComment(context, id)
+	if err != nil {
+		return nil, severity, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "CommentDeleted"))
+	}
+	if !reaction.IsEmoji(emoji) {
+		return nil, http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidEmoji"))
+	}
+
+	tx := repo.db.Begin()
+
+	put, err := reaction.Toggle(tx, models.ReactionTargetComment, id, context.UserId, emoji)
+	if err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// log operation
+	change := &models.LogChange{Field: "reaction", FromValue: emoji}
+	if put {
+		change = &models.LogChange{Field: "reaction", ToValue: emoji}
+	}
+	changesJson, err := json.Marshal([]*models.LogChange{change})
+	if err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+	boardId, err := repo.getBoardIdOfComment(comment)
+	if boardId == "" || err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "reactcomment",
+		ActionTargetID: id,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, severity, err
+	}
+
+	tx.Commit()
+
+	summaries, err := reaction.Load(repo.db, models.ReactionTargetComment, []string{id}, context.UserId)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return summaries[id], http.StatusOK, nil
+}
+
+// GetRevisions returns the versions of the content of a comment, oldest first, the last one being the current content.
+// A comment never edited has no revisions.
+func (repo CommentRepository) GetRevisions(context models.Context, id string) ([]*models.CommentRevision, int, error) {
+	_, severity, err := repo.GetComment(context, id)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	revisions := []*models.CommentRevision{}
+	err = repo.db.Where("comment_id = ?", id).Order("created_at ASC").Find(&revisions).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return revisions, http.StatusOK, nil
+}
+
+// createRevisions records in tx the new content of an edited comment, preceded by its original content on its first edit
+func (repo CommentRepository) createRevisions(tx *gorm.DB, context models.Context, commentBefore *models.Comment, comment *models.Comment) error {
+	var count int64
+	err := tx.Model(&models.CommentRevision{}).Where("comment_id = ?", comment.ID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		err = tx.Create(&models.CommentRevision{
+			ID:        uuid.NewString(),
+			CommentID: comment.ID,
+			UserID:    commentBefore.UserID,
+			Content:   commentBefore.Content,
+			CreatedAt: commentBefore.CreatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Create(&models.CommentRevision{
+		ID:        uuid.NewString(),
+		CommentID: comment.ID,
+		UserID:    context.UserId,
+		Content:   comment.Content,
+		CreatedAt: comment.UpdatedAt,
+	}).Error
+}
+
 func (repo CommentRepository) getBoardIdOfComment(comment *models.Comment) (string, error) {
 	var card *models.Card
 	err := repo.db.
@@ -1,42 +1,121 @@
 package comment
 
-import "trellode-go/internal/models"
+import (
+	"errors"
+	"net/http"
+	"trellode-go/internal/member"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+)
 
 type CommentServiceInterface interface {
 	GetComment(models.Context, string) (*models.Comment, int, error)
-	GetComments(models.Context, string) ([]*models.Comment, int, error)
+	GetComments(models.Context, string, int, string) ([]*models.Comment, string, int, error)
 	CreateComment(models.Context, *models.Comment) (string, int, error)
 	UpdateComment(models.Context, *models.Comment) (int, error)
 	DeleteComment(models.Context, string) (int, error)
+	ToggleReaction(models.Context, string, string) ([]models.ReactionSummary, int, error)
+	GetRevisions(models.Context, string) ([]*models.CommentRevision, int, error)
 }
 
 type CommentService struct {
-	repo CommentRepositoryInterface
+	repo          CommentRepositoryInterface
+	memberService member.MemberService
 }
 
 // NewPersonService returns a service to manipulate unit
-func NewCommentService(repo CommentRepositoryInterface) CommentService {
+func NewCommentService(repo CommentRepositoryInterface, memberService member.MemberService) CommentService {
 	return CommentService{
-		repo: repo,
+		repo:          repo,
+		memberService: memberService,
 	}
 }
 
 func (p CommentService) GetComment(context models.Context, id string) (*models.Comment, int, error) {
+	severity, err := p.memberService.CheckCommentRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
 	return p.repo.GetComment(context, id)
 }
 
-func (p CommentService) GetComments(context models.Context, boardId string) ([]*models.Comment, int, error) {
-	return p.repo.GetComments(context, boardId)
+func (p CommentService) GetComments(context models.Context, cardId string, pageSize int, cursor string) ([]*models.Comment, string, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleViewer)
+	if err != nil {
+		return nil, "", severity, err
+	}
+
+	return p.repo.GetComments(context, cardId, pageSize, cursor)
 }
 
 func (p CommentService) CreateComment(context models.Context, board *models.Comment) (string, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, board.CardID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
 	return p.repo.CreateComment(context, board)
 }
 
 func (p CommentService) UpdateComment(context models.Context, board *models.Comment) (int, error) {
+	severity, err := p.checkAuthor(context, board.ID)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.UpdateComment(context, board)
 }
 
 func (p CommentService) DeleteComment(context models.Context, id string) (int, error) {
+	severity, err := p.checkAuthor(context, id)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.DeleteComment(context, id)
 }
+
+func (p CommentService) ToggleReaction(context models.Context, id string, emoji string) ([]models.ReactionSummary, int, error) {
+	severity, err := p.memberService.CheckCommentRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return p.repo.ToggleReaction(context, id, emoji)
+}
+
+func (p CommentService) GetRevisions(context models.Context, id string) ([]*models.CommentRevision, int, error) {
+	severity, err := p.memberService.CheckCommentRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return p.repo.GetRevisions(context, id)
+}
+
+// checkAuthor returns an error if the current user is neither the author of the comment (and still an editor of its board) nor a board owner
+func (p CommentService) checkAuthor(context models.Context, id string) (int, error) {
+	severity, err := p.memberService.CheckCommentRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+	comment, severity, err := p.repo.GetComment(context, id)
+	if err != nil {
+		return severity, err
+	}
+	if comment.UserID == context.UserId {
+		return http.StatusOK, nil
+	}
+
+	// board owners moderate the comments of others
+	severity, err = p.memberService.CheckCommentRole(context, id, models.BoardRoleOwner)
+	if err != nil && severity == http.StatusForbidden {
+		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "NotCommentAuthor"))
+	}
+	if err != nil {
+		return severity, err
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"trellode-go/internal/models"
+)
+
+// boardToCSV flattens the open lists and cards of a board into a CSV sheet, one line per card
+func boardToCSV(board *models.Board) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	err := writer.Write([]string{"list", "card", "description", "checklist items checked", "checklist items", "comments", "created at"})
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range board.Lists {
+		if list.ArchivedAt != nil {
+			continue
+		}
+		for _, card := range list.Cards {
+			if card.ArchivedAt != nil {
+				continue
+			}
+			checked, total := checklistProgress(&card)
+			err := writer.Write([]string{
+				list.Title,
+				card.Title,
+				card.Description,
+				strconv.Itoa(checked),
+				strconv.Itoa(total),
+				strconv.Itoa(commentCount(&card)),
+				card.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// boardToMarkdown renders the open lists and cards of a board as a Markdown document, one heading per list
+func boardToMarkdown(board *models.Board) []byte {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n", board.Title)
+	for _, list := range board.Lists {
+		if list.ArchivedAt != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n## %s\n", list.Title)
+		for _, card := range list.Cards {
+			if card.ArchivedAt != nil {
+				continue
+			}
+			fmt.Fprintf(&sb, "\n### %s\n", card.Title)
+			if card.Description != "" {
+				fmt.Fprintf(&sb, "\n%s\n", strings.TrimSpace(card.Description))
+			}
+			for _, checklist := range card.Checklists {
+				if checklist.ArchivedAt != nil {
+					continue
+				}
+				checked := 0
+				for _, item := range checklist.Items {
+					if item.Checked {
+						checked++
+					}
+				}
+				fmt.Fprintf(&sb, "\n**%s** (%d/%d)\n\n", checklist.Title, checked, len(checklist.Items))
+				for _, item := range checklist.Items {
+					box := " "
+					if item.Checked {
+						box = "x"
+					}
+					fmt.Fprintf(&sb, "- [%s] %s\n", box, item.Title)
+				}
+			}
+			if comments := commentCount(&card); comments > 0 {
+				fmt.Fprintf(&sb, "\n_%d comment(s)_\n", comments)
+			}
+		}
+	}
+
+	return []byte(sb.String())
+}
+
+// commentCount returns the number of comments of a card, deleted ones excluded
+func commentCount(card *models.Card) int {
+	count := 0
+	for _, comment := range card.Comments {
+		if comment.DeletedAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// checklistProgress returns the number of checked items and the total number of items of the open checklists of a card
+func checklistProgress(card *models.Card) (int, int) {
+	checked, total := 0, 0
+	for _, checklist := range card.Checklists {
+		if checklist.ArchivedAt != nil {
+			continue
+		}
+		for _, item := range checklist.Items {
+			if item.Checked {
+				checked++
+			}
+			total++
+		}
+	}
+
+	return checked, total
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"trellode-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testBoard() *models.Board {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return &models.Board{
+		Title: "Sprint",
+		Lists: []models.List{
+			{
+				Title: "To Do",
+				Cards: []models.Card{
+					{
+						Title:       "Task 1",
+						Description: "first, task",
+						CreatedAt:   now,
+						Comments:    []models.Comment{{Content: "a"}, {Content: "b"}, {DeletedAt: &now}},
+						Checklists: []models.Checklist{
+							{Title: "Steps", Items: []models.ChecklistItem{{Title: "one", Checked: true}, {Title: "two"}}},
+						},
+					},
+					{Title: "Task 2", CreatedAt: now, Comments: []models.Comment{{DeletedAt: &now}}},
+					{Title: "Archived", ArchivedAt: &now},
+				},
+			},
+			{Title: "Old", ArchivedAt: &now},
+		},
+	}
+}
+
+func TestBoardToCSV(t *testing.T) {
+	data, err := boardToCSV(testBoard())
+	assert.Nil(t, err)
+	assert.Equal(t, "list,card,description,checklist items checked,checklist items,comments,created at\n"+
+		"To Do,Task 1,\"first, task\",1,2,2,2024-05-01 10:00:00\n"+
+		"To Do,Task 2,,0,0,0,2024-05-01 10:00:00\n", string(data))
+}
+
+func TestBoardToMarkdown(t *testing.T) {
+	markdown := string(boardToMarkdown(testBoard()))
+	assert.True(t, strings.HasPrefix(markdown, "# Sprint\n\n## To Do\n\n### Task 1\n"))
+	assert.Contains(t, markdown, "**Steps** (1/2)\n\n- [x] one\n- [ ] two\n")
+	// deleted comments are not counted
+	assert.Contains(t, markdown, "_2 comment(s)_")
+	assert.NotContains(t, markdown, "_0 comment(s)_")
+	assert.NotContains(t, markdown, "Archived")
+	assert.NotContains(t, markdown, "## Old")
+}
@@ -0,0 +1,422 @@
+package export
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"trellode-go/internal/log"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/rank"
+	"trellode-go/internal/utils/tools"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ExportRepository struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	logService log.LogService
+}
+
+type ExportRepositoryInterface interface {
+	ExportBoard(models.Context, string) (*models.BoardExport, int, error)
+	ImportBoard(models.Context, *models.BoardExport) (string, int, error)
+}
+
+func NewExportRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) ExportRepository {
+	return ExportRepository{
+		db:         db,
+		log:        log,
+		logService: logService,
+	}
+}
+
+// ExportBoard returns the whole board (archived lists and cards included) with its activity logs and the users involved
+// (authors of comments and logs, assignees of cards)
+func (repo ExportRepository) ExportBoard(context models.Context, id string) (*models.BoardExport, int, error) {
+	var board models.Board
+	err := repo.db.
+		Preload("Background").
+		Preload("Lists", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards.Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("parent_id IS NOT NULL, created_at ASC, id ASC")
+		}).
+		Preload("Lists.Cards.Checklists", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Lists.Cards.Checklists.Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards.Labels").
+		Preload("Lists.Cards.Assignees").
+		Preload("Lists.Cards.CustomFieldValues").
+		Preload("Labels", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name ASC")
+		}).
+		Preload("CustomFields", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("id = ?", id).
+		First(&board).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if board.ID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	}
+	models.NumberLists(board.Lists)
+
+	logs := []*models.Log{}
+	err = repo.db.
+		Where("board_id = ?", id).
+		Order("created_at ASC").
+		Find(&logs).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// collect authors and assignees
+	userIds := []string{board.UserID}
+	for _, list := range board.Lists {
+		for _, card := range list.Cards {
+			for _, comment := range card.Comments {
+				userIds = append(userIds, comment.UserID)
+			}
+			for _, assignee := range card.Assignees {
+				userIds = append(userIds, assignee.ID)
+			}
+		}
+	}
+	for _, log := range logs {
+		userIds = append(userIds, log.UserID)
+	}
+	users := []*models.User{}
+	err = repo.db.
+		Where("id IN ?", tools.RemoveDuplicateStr(userIds)).
+		Find(&users).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &models.BoardExport{
+		Version:    models.BoardExportVersion,
+		ExportedAt: time.Now(),
+		Board:      &board,
+		Logs:       logs,
+		Users:      users,
+	}, http.StatusOK, nil
+}
+
+// ImportBoard recreates an exported board under the current user with new IDs.
+// The export is written by whoever uploads it, so its comments and logs are all attributed to the current user.
+func (repo ExportRepository) ImportBoard(context models.Context, export *models.BoardExport) (string, int, error) {
+	if export.Version < 1 || export.Version > models.BoardExportVersion {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "UnsupportedExportVersion"))
+	}
+	if export.Board == nil {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidExport"))
+	}
+
+	// only the exported user with the email of the current user is mapped, to the current user
+	var currentUser models.User
+	err := repo.db.Where("id = ?", context.UserId).First(&currentUser).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	userIds := map[string]string{}
+	for _, user := range export.Users {
+		if currentUser.ID != "" && strings.EqualFold(user.Email, currentUser.Email) {
+			userIds[user.ID] = currentUser.ID
+		}
+	}
+	// exported ID -> new ID
+	ids := map[string]string{}
+	newId := func(id string) string {
+		ids[id] = uuid.NewString()
+		return ids[id]
+	}
+
+	source := export.Board
+
+	tx := repo.db.Begin()
+
+	board := models.Board{
+		ID:         newId(source.ID),
+		UserID:     context.UserId,
+		Title:      source.Title,
+		IsTemplate: source.IsTemplate,
+		CreatedAt:  source.CreatedAt,
+	}
+
+	// backgrounds belong to a user, so a new one is created for the current user
+	if source.Background != nil && source.Background.Data != "" {
+		background := models.Background{
+			ID:     uuid.NewString(),
+			UserID: context.UserId,
+			Data:   source.Background.Data,
+			Color:  source.Background.Color,
+		}
+		err := tx.Create(&background).Error
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+		board.BackgroundID = background.ID
+	}
+
+	omitted := []string{"Background", "Lists", "Labels", "CustomFields"}
+	if board.BackgroundID == "" {
+		omitted = append(omitted, "BackgroundID")
+	}
+	err = tx.Omit(omitted...).Create(&board).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// creator is owner of the board
+	err = tx.Create(&models.BoardMember{
+		BoardID: board.ID,
+		UserID:  context.UserId,
+		Role:    models.BoardRoleOwner,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	for _, sourceLabel := range source.Labels {
+		label := models.Label{
+			ID:        newId(sourceLabel.ID),
+			BoardID:   board.ID,
+			Name:      sourceLabel.Name,
+			Color:     sourceLabel.Color,
+			CreatedAt: sourceLabel.CreatedAt,
+		}
+		err = tx.Create(&label).Error
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+	}
+
+	for _, sourceField := range source.CustomFields {
+		field := models.CustomField{
+			ID:        newId(sourceField.ID),
+			BoardID:   board.ID,
+			Name:      sourceField.Name,
+			Type:      sourceField.Type,
+			Options:   sourceField.Options,
+			Position:  sourceField.Position,
+			CreatedAt: sourceField.CreatedAt,
+		}
+		err = tx.Create(&field).Error
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+	}
+
+	// lists, cards and items get new ranks in the order of the export
+	listRanks := rank.Sequence(len(source.Lists))
+	for i, sourceList := range source.Lists {
+		list := models.List{
+			ID:         newId(sourceList.ID),
+			BoardID:    board.ID,
+			Title:      sourceList.Title,
+			Rank:       listRanks[i],
+			CreatedAt:  sourceList.CreatedAt,
+			ArchivedAt: sourceList.ArchivedAt,
+		}
+		err = tx.Omit("Cards").Create(&list).Error
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+
+		cardRanks := rank.Sequence(len(sourceList.Cards))
+		for j, sourceCard := range sourceList.Cards {
+			card := models.Card{
+				ID:          newId(sourceCard.ID),
+				ListID:      list.ID,
+				Title:       sourceCard.Title,
+				Description: sourceCard.Description,
+				Rank:        cardRanks[j],
+				StartAt:     sourceCard.StartAt,
+				DueAt:       sourceCard.DueAt,
+				CompletedAt: sourceCard.CompletedAt,
+				CreatedAt:   sourceCard.CreatedAt,
+				ArchivedAt:  sourceCard.ArchivedAt,
+			}
+			// attachments are not exported, neither are image covers
+			if sourceCard.Cover.AttachmentID == nil {
+				card.Cover = sourceCard.Cover
+			}
+			err = tx.Omit("Comments", "Checklists", "Labels", "Assignees", "CustomFieldValues").Create(&card).Error
+			if err != nil {
+				tx.Rollback()
+				return "", http.StatusInternalServerError, err
+			}
+
+			for _, sourceLabel := range sourceCard.Labels {
+				labelId, ok := ids[sourceLabel.ID]
+				if !ok {
+					continue
+				}
+				err = tx.Create(&models.CardLabel{CardID: card.ID, LabelID: labelId}).Error
+				if err != nil {
+					tx.Rollback()
+					return "", http.StatusInternalServerError, err
+				}
+			}
+
+			for _, sourceValue := range sourceCard.CustomFieldValues {
+				fieldId, ok := ids[sourceValue.CustomFieldID]
+				if !ok {
+					continue
+				}
+				value := models.CustomFieldValue{
+					CardID:        card.ID,
+					CustomFieldID: fieldId,
+					Value:         sourceValue.Value,
+				}
+				err = tx.Omit("CustomField").Create(&value).Error
+				if err != nil {
+					tx.Rollback()
+					return "", http.StatusInternalServerError, err
+				}
+			}
+
+			// the importing user is the only member of the new board, other assignees are dropped
+			for _, sourceAssignee := range sourceCard.Assignees {
+				if userIds[sourceAssignee.ID] != context.UserId {
+					continue
+				}
+				err = tx.Create(&models.CardAssignee{CardID: card.ID, UserID: context.UserId}).Error
+				if err != nil {
+					tx.Rollback()
+					return "", http.StatusInternalServerError, err
+				}
+			}
+
+			// comments first, then replies, whatever the order of the file, so that every parent is mapped before its replies
+			for _, sourceComment := range append(commentsByLevel(sourceCard.Comments, true), commentsByLevel(sourceCard.Comments, false)...) {
+				comment := models.Comment{
+					ID:        newId(sourceComment.ID),
+					CardID:    card.ID,
+					UserID:    context.UserId,
+					Content:   sourceComment.Content,
+					CreatedAt: sourceComment.CreatedAt,
+					UpdatedAt: sourceComment.UpdatedAt,
+					EditedAt:  sourceComment.EditedAt,
+					DeletedAt: sourceComment.DeletedAt,
+				}
+				if sourceComment.ParentID != nil {
+					parentId, ok := ids[*sourceComment.ParentID]
+					if !ok {
+						continue
+					}
+					comment.ParentID = &parentId
+				}
+				err = tx.Omit("Replies").Create(&comment).Error
+				if err != nil {
+					tx.Rollback()
+					return "", http.StatusInternalServerError, err
+				}
+			}
+
+			for _, sourceChecklist := range sourceCard.Checklists {
+				checklist := models.Checklist{
+					ID:         newId(sourceChecklist.ID),
+					CardID:     card.ID,
+					Title:      sourceChecklist.Title,
+					CreatedAt:  sourceChecklist.CreatedAt,
+					ArchivedAt: sourceChecklist.ArchivedAt,
+				}
+				err = tx.Omit("Items").Create(&checklist).Error
+				if err != nil {
+					tx.Rollback()
+					return "", http.StatusInternalServerError, err
+				}
+
+				itemRanks := rank.Sequence(len(sourceChecklist.Items))
+				for k, sourceItem := range sourceChecklist.Items {
+					item := models.ChecklistItem{
+						ID:          newId(sourceItem.ID),
+						ChecklistID: checklist.ID,
+						Title:       sourceItem.Title,
+						Rank:        itemRanks[k],
+						Checked:     sourceItem.Checked,
+						CreatedAt:   sourceItem.CreatedAt,
+					}
+					err = tx.Create(&item).Error
+					if err != nil {
+						tx.Rollback()
+						return "", http.StatusInternalServerError, err
+					}
+				}
+			}
+		}
+	}
+
+	// activity history is kept as is, with remapped IDs
+	for _, sourceLog := range export.Logs {
+		targetId, ok := ids[sourceLog.ActionTargetID]
+		if !ok {
+			targetId, ok = userIds[sourceLog.ActionTargetID]
+		}
+		if !ok {
+			targetId = sourceLog.ActionTargetID
+		}
+		log := models.Log{
+			ID:             uuid.NewString(),
+			UserID:         context.UserId,
+			BoardID:        board.ID,
+			Action:         sourceLog.Action,
+			ActionTargetID: targetId,
+			Changes:        sourceLog.Changes,
+			CreatedAt:      sourceLog.CreatedAt,
+		}
+		err = tx.Omit("User").Create(&log).Error
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        board.ID,
+		Action:         "importboard",
+		ActionTargetID: board.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return board.ID, http.StatusCreated, nil
+}
+
+// commentsByLevel returns the top-level comments, or the replies, keeping their order
+func commentsByLevel(comments []models.Comment, topLevel bool) []models.Comment {
+	filtered := []models.Comment{}
+	for _, comment := range comments {
+		if (comment.ParentID == nil) == topLevel {
+			filtered = append(filtered, comment)
+		}
+	}
+	return filtered
+}
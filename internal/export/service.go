@@ -0,0 +1,63 @@
+package export
+
+import (
+	"errors"
+	"net/http"
+	"trellode-go/internal/member"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+)
+
+type ExportServiceInterface interface {
+	ExportBoard(models.Context, string) (*models.BoardExport, int, error)
+	ExportBoardAs(models.Context, string, string) ([]byte, string, int, error)
+	ImportBoard(models.Context, *models.BoardExport) (string, int, error)
+}
+
+type ExportService struct {
+	repo          ExportRepositoryInterface
+	memberService member.MemberService
+}
+
+// NewExportService returns a service to export and import boards
+func NewExportService(repo ExportRepositoryInterface, memberService member.MemberService) ExportService {
+	return ExportService{
+		repo:          repo,
+		memberService: memberService,
+	}
+}
+
+func (s ExportService) ExportBoard(context models.Context, id string) (*models.BoardExport, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.ExportBoard(context, id)
+}
+
+// ExportBoardAs flattens a board into a csv or markdown document and returns it with its content type
+func (s ExportService) ExportBoardAs(context models.Context, id string, format string) ([]byte, string, int, error) {
+	if format != "csv" && format != "markdown" {
+		return nil, "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "UnsupportedExportFormat"))
+	}
+
+	export, severity, err := s.ExportBoard(context, id)
+	if err != nil {
+		return nil, "", severity, err
+	}
+
+	if format == "csv" {
+		data, err := boardToCSV(export.Board)
+		if err != nil {
+			return nil, "", http.StatusInternalServerError, err
+		}
+		return data, "text/csv; charset=utf-8", http.StatusOK, nil
+	}
+
+	return boardToMarkdown(export.Board), "text/markdown; charset=utf-8", http.StatusOK, nil
+}
+
+func (s ExportService) ImportBoard(context models.Context, export *models.BoardExport) (string, int, error) {
+	return s.repo.ImportBoard(context, export)
+}
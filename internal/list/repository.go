@@ -8,9 +8,17 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"trellode-go/internal/automation"
 	"trellode-go/internal/log"
 	"trellode-go/internal/models"
+	"trellode-go/internal/utils/blocked"
+	"trellode-go/internal/utils/clone"
+	"trellode-go/internal/utils/commentcount"
 	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/rank"
+	"trellode-go/internal/utils/reaction"
+	"trellode-go/internal/utils/storage"
+	"trellode-go/internal/utils/wip"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -21,6 +29,8 @@ type ListRepository struct {
 	db         *gorm.DB
 	log        *zap.Logger
 	logService log.LogService
+	storage    storage.Storage
+	automation automation.Engine
 }
 
 type ListRepositoryInterface interface {
@@ -28,15 +38,20 @@ type ListRepositoryInterface interface {
 	CreateList(models.Context, *models.List) (string, int, error)
 	UpdateList(models.Context, *models.List) (int, error)
 	UpdateCardsOrder(models.Context, string, string) (int, error)
-	MoveCardToList(models.Context, int, int, string, int) (int, error)
+	MoveCardToList(models.Context, string, int, int, string, int) (string, int, error)
+	MoveCard(models.Context, string, string, int, string, int) (string, int, error)
+	MoveList(models.Context, string, string, int) (int, error)
 	DeleteList(models.Context, string) (int, error)
+	CopyList(models.Context, string, string, string, bool) (string, int, error)
 }
 
-func NewListRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) ListRepository {
+func NewListRepository(db *gorm.DB, log *zap.Logger, logService log.LogService, storage storage.Storage) ListRepository {
 	return ListRepository{
 		db:         db,
 		log:        log,
 		logService: logService,
+		storage:    storage,
+		automation: automation.NewEngine(logService, log),
 	}
 }
 
@@ -44,10 +59,7 @@ func (repo ListRepository) GetList(context models.Context, id string) (*models.L
 	var list *models.List
 	err := repo.db.
 		Preload("Cards", func(db *gorm.DB) *gorm.DB {
-			return db.Where("archived_at IS NULL").Order("position ASC")
-		}).
-		Preload("Cards.Comments", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC")
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
 		}).
 		Where("id = ?", id).
 		First(&list).Error
@@ -57,15 +69,24 @@ func (repo ListRepository) GetList(context models.Context, id string) (*models.L
 	if list.ID == "" {
 		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
 	}
+	models.NumberCards(list.Cards)
+	list.CardCount = len(list.Cards)
+
+	// comments are loaded page by page, the list only gives how many there are
+	commentCountOfCard, err := commentcount.Load(repo.db, "SELECT id FROM cards WHERE list_id = ?", list.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for i := range list.Cards {
+		list.Cards[i].CommentCount = commentCountOfCard[list.Cards[i].ID]
+	}
 
 	return list, http.StatusOK, nil
 }
 
 func (repo ListRepository) CreateList(context models.Context, list *models.List) (string, int, error) {
-	// get lists of board to determine position of new list
 	var board *models.Board
 	err := repo.db.
-		Preload("Lists", repo.db.Where("archived_at IS NULL")).
 		Where("id = ?", list.BoardID).
 		First(&board).Error
 	if err != nil {
@@ -74,10 +95,18 @@ func (repo ListRepository) CreateList(context models.Context, list *models.List)
 	if board.ID == "" {
 		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
 	}
+	if list.WipLimit < 0 {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidWipLimit"))
+	}
+	// the new list goes at the end of the board
+	lastRank, err := lastListRank(repo.db, board.ID)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
 
 	list.ID = uuid.NewString()
 	list.ArchivedAt = nil
-	list.Position = len(board.Lists) + 1
+	list.Rank = rank.Between(lastRank, "")
 
 	tx := repo.db.Begin()
 
@@ -114,18 +143,11 @@ func (repo ListRepository) UpdateList(context models.Context, list *models.List)
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
 	}
 
-	// get lists of board to reassign positions (if something was archived or restored)
-	var board *models.Board
-	err = repo.db.
-		Preload("Lists", repo.db.Where("archived_at IS NULL")).
-		Where("id = ?", list.BoardID).
-		First(&board).Error
-	if err != nil {
-		return http.StatusInternalServerError, err
-	}
-	if board.ID == "" {
-		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	if list.WipLimit < 0 {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidWipLimit"))
 	}
+	// lists are ordered through their own endpoints
+	list.Rank = listBefore.Rank
 
 	// what changed?
 	changes, err := whatChanged(listBefore, list)
@@ -146,24 +168,6 @@ func (repo ListRepository) UpdateList(context models.Context, list *models.List)
 		return http.StatusInternalServerError, err
 	}
 
-	// reassign positions
-	newPosition := 0
-	for _, loopList := range board.Lists {
-		if loopList.ID == list.ID {
-			loopList = *list
-		}
-		if loopList.ArchivedAt != nil {
-			continue
-		}
-		newPosition++
-		list.Position = newPosition
-		err := tx.Model(&models.List{}).Where("id = ?", loopList.ID).Update("position", newPosition).Error
-		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
-		}
-	}
-
 	// log operation
 	operation := "updatelist"
 	if listBefore.ArchivedAt == nil && list.ArchivedAt != nil {
@@ -199,23 +203,26 @@ func (repo ListRepository) UpdateCardsOrder(context models.Context, listId strin
 		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
 	}
 
-	tx := repo.db.Begin()
-
 	idsOrderedSplit := strings.Split(idsOrdered, ",")
 
-	for i, id := range idsOrderedSplit {
-		var card *models.Card
-		err := tx.Where("id = ?", id).First(&card).Error
-		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
-		}
-		if card.ID == "" {
-			tx.Rollback()
+	cardRanks := map[string]string{}
+	for _, card := range list.Cards {
+		cardRanks[card.ID] = card.Rank
+	}
+	orderedRanks := []string{}
+	for _, id := range idsOrderedSplit {
+		cardRank, ok := cardRanks[id]
+		if !ok {
 			return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
 		}
-		card.Position = i + 1
-		err = tx.Save(&card).Error
+		orderedRanks = append(orderedRanks, cardRank)
+	}
+
+	tx := repo.db.Begin()
+
+	// only the cards out of order get a new rank
+	for i, newRank := range rank.Reorder(orderedRanks) {
+		err = tx.Model(&models.Card{}).Where("id = ?", idsOrderedSplit[i]).Update("rank_key", newRank).Error
 		if err != nil {
 			tx.Rollback()
 			return http.StatusInternalServerError, err
@@ -239,70 +246,106 @@ func (repo ListRepository) UpdateCardsOrder(context models.Context, listId strin
 	return http.StatusAccepted, nil
 }
 
-// indexes are on a 0..n basis
-func (repo ListRepository) MoveCardToList(context models.Context, sourceListIndex int, sourceCardIndex int, targetListId string, targetCardIndex int) (int, error) {
+// indexes are on a 0..n basis, the source list is taken from the board sourceBoardId
+// (the board of the target list when empty).
+// Deprecated: indexes may designate another card when the board changed meanwhile, use MoveCard
+func (repo ListRepository) MoveCardToList(context models.Context, sourceBoardId string, sourceListIndex int, sourceCardIndex int, targetListId string, targetCardIndex int) (string, int, error) {
 	// get sourceList from db
 	targetList, severity, err := repo.GetList(context, targetListId)
 	if err != nil {
-		return severity, err
+		return "", severity, err
 	}
 	if targetList.ID == "" {
-		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
 	}
 	fmt.Printf("---------- targetList.ID: %s, cards: %d\n", targetList.ID, len(targetList.Cards))
 
 	// get board to determine the target list from index
-	var board *models.Board
-	err = repo.db.
-		Preload("Lists", func(db *gorm.DB) *gorm.DB {
-			return db.Where("archived_at IS NULL").Order("position ASC")
-		}).
-		Preload("Lists.Cards", func(db *gorm.DB) *gorm.DB {
-			return db.Where("archived_at IS NULL").Order("position ASC")
-		}).
-		Where("id = ?", targetList.BoardID).
-		First(&board).Error
+	board, severity, err := repo.getBoardWithCards(context, targetList.BoardID)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return "", severity, err
 	}
-	if board.ID == "" {
-		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	sourceBoard := board
+	if sourceBoardId != "" && sourceBoardId != board.ID {
+		sourceBoard, severity, err = repo.getBoardWithCards(context, sourceBoardId)
+		if err != nil {
+			return "", severity, err
+		}
+	}
+	if sourceListIndex < 0 || sourceListIndex >= len(sourceBoard.Lists) {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
 	}
 
 	// get source list from index
-	sourceList := board.Lists[sourceListIndex]
+	sourceList := sourceBoard.Lists[sourceListIndex]
+	if sourceCardIndex < 0 || sourceCardIndex >= len(sourceList.Cards) {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
+	}
 
 	// get source card
 	sourceCard := sourceList.Cards[sourceCardIndex]
 
-	tx := repo.db.Begin()
-
-	// update index of cards in targetList from targetCardIndex, shift them by one to make room for new card
-	for i := targetCardIndex; i < len(targetList.Cards); i++ {
-		card := targetList.Cards[i]
-		fmt.Printf("---------- set target list card with id %s position %d\n", card.ID, card.Position+1)
-		err = tx.Model(&card).Update("Position", card.Position+1).Error
+	warning := ""
+	if sourceList.ID != targetList.ID {
+		severity, err = blocked.Check(context, repo.db, &sourceCard, targetList)
 		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
+			return "", severity, err
+		}
+		warning, severity, err = wip.Check(context, repo.db, targetList, sourceCard.ID)
+		if err != nil {
+			return "", severity, err
 		}
 	}
 
-	// change the listId and position of source card to assign it the targetList.ID with new position
-	err = tx.Model(&sourceCard).Updates(models.Card{ListID: targetList.ID, Position: targetCardIndex + 1}).Error
+	tx := repo.db.Begin()
+
+	// the card gets a rank between its new neighbours
+	targetRanks := []string{}
+	for _, card := range targetList.Cards {
+		if card.ID != sourceCard.ID {
+			targetRanks = append(targetRanks, card.Rank)
+		}
+	}
+	err = tx.Model(&sourceCard).Updates(map[string]interface{}{"list_id": targetList.ID, "rank_key": rank.At(targetRanks, targetCardIndex), "version": gorm.Expr("version + 1")}).Error
 	if err != nil {
 		tx.Rollback()
-		return http.StatusInternalServerError, err
+		return "", http.StatusInternalServerError, err
 	}
 
-	// reorder sourceList.Cards, shift by -1 for indexes > sourceCardIndex
-	for i := sourceCardIndex + 1; i < len(sourceList.Cards); i++ {
-		card := sourceList.Cards[i]
-		fmt.Printf("---------- set source list card with id %s position %d\n", card.ID, card.Position-1)
-		err = tx.Model(&card).Update("Position", card.Position-1).Error
+	changesJson := []byte{}
+	if sourceBoard.ID != board.ID {
+		// labels and custom fields are matched by name on the target board
+		mapping, err := clone.Match(tx, sourceBoard.ID, board.ID)
 		if err != nil {
 			tx.Rollback()
-			return http.StatusInternalServerError, err
+			return "", http.StatusInternalServerError, err
+		}
+		err = clone.MoveCards(tx, []string{sourceCard.ID}, board.ID, mapping)
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+
+		changesJson, err = json.Marshal([]*models.LogChange{{
+			Field:     "board",
+			FromValue: sourceBoard.Title,
+			ToValue:   board.Title,
+		}})
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+		// log operation on the target board too
+		_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+			UserID:         context.UserId,
+			BoardID:        board.ID,
+			Action:         "movecardtolist",
+			ActionTargetID: targetList.ID,
+			Changes:        string(changesJson),
+		})
+		if err != nil {
+			tx.Rollback()
+			return "", severity, err
 		}
 	}
 
@@ -312,17 +355,275 @@ func (repo ListRepository) MoveCardToList(context models.Context, sourceListInde
 		BoardID:        sourceList.BoardID,
 		Action:         "movecardtolist",
 		ActionTargetID: sourceList.ID,
+		Changes:        string(changesJson),
 	})
 	if err != nil {
 		tx.Rollback()
+		return "", severity, err
+	}
+
+	// run the rules of the target board
+	if sourceList.ID != targetList.ID {
+		err = repo.automation.Run(context, tx, automation.Event{
+			Trigger: models.RuleTriggerCardMoved,
+			BoardID: board.ID,
+			CardID:  sourceCard.ID,
+			ListID:  targetList.ID,
+		})
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+	}
+
+	tx.Commit()
+
+	return warning, http.StatusAccepted, nil
+}
+
+// MoveCard moves the card cardId from the list fromListId to the list toListId, possibly on another board,
+// at the given position (at the end when 0). The move fails with a conflict when the card is not in fromListId
+// or not at version anymore, i.e. when someone else moved or updated it meanwhile
+func (repo ListRepository) MoveCard(context models.Context, cardId string, fromListId string, version int, toListId string, position int) (string, int, error) {
+	var card models.Card
+	err := repo.db.Where("id = ?", cardId).First(&card).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if card.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
+	}
+	if card.ListID != fromListId || card.Version != version {
+		return "", http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "CardMoveConflict"))
+	}
+
+	var sourceList models.List
+	err = repo.db.Where("id = ?", fromListId).First(&sourceList).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	targetList, severity, err := repo.GetList(context, toListId)
+	if err != nil {
+		return "", severity, err
+	}
+	warning := ""
+	if sourceList.ID != targetList.ID {
+		severity, err = blocked.Check(context, repo.db, &card, targetList)
+		if err != nil {
+			return "", severity, err
+		}
+		warning, severity, err = wip.Check(context, repo.db, targetList, card.ID)
+		if err != nil {
+			return "", severity, err
+		}
+	}
+	// the card gets a rank between its new neighbours
+	targetRanks := []string{}
+	for _, targetCard := range targetList.Cards {
+		if targetCard.ID != card.ID {
+			targetRanks = append(targetRanks, targetCard.Rank)
+		}
+	}
+
+	changes := []*models.LogChange{{
+		Field:     "list",
+		FromValue: sourceList.Title,
+		ToValue:   targetList.Title,
+	}}
+	if sourceList.BoardID != targetList.BoardID {
+		var boards []models.Board
+		err = repo.db.Where("id IN ?", []string{sourceList.BoardID, targetList.BoardID}).Find(&boards).Error
+		if err != nil {
+			return "", http.StatusInternalServerError, err
+		}
+		change := &models.LogChange{Field: "board"}
+		for _, board := range boards {
+			if board.ID == sourceList.BoardID {
+				change.FromValue = board.Title
+			} else {
+				change.ToValue = board.Title
+			}
+		}
+		changes = append(changes, change)
+	}
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	// only move the card if it did not change since it was read
+	result := tx.Model(&models.Card{}).
+		Where("id = ? AND list_id = ? AND version = ?", cardId, fromListId, version).
+		Updates(map[string]interface{}{"list_id": toListId, "rank_key": rank.At(targetRanks, position-1), "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return "", http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "CardMoveConflict"))
+	}
+
+	if sourceList.BoardID != targetList.BoardID {
+		// labels and custom fields are matched by name on the target board
+		mapping, err := clone.Match(tx, sourceList.BoardID, targetList.BoardID)
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+		err = clone.MoveCards(tx, []string{cardId}, targetList.BoardID, mapping)
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+	}
+
+	// log operation, on both boards when the card changed board
+	boardIds := []string{sourceList.BoardID}
+	if sourceList.BoardID != targetList.BoardID {
+		boardIds = append(boardIds, targetList.BoardID)
+	}
+	for _, boardId := range boardIds {
+		_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+			UserID:         context.UserId,
+			BoardID:        boardId,
+			Action:         "movecard",
+			ActionTargetID: cardId,
+			Changes:        string(changesJson),
+		})
+		if err != nil {
+			tx.Rollback()
+			return "", severity, err
+		}
+	}
+
+	// run the rules of the target board
+	if sourceList.ID != targetList.ID {
+		err = repo.automation.Run(context, tx, automation.Event{
+			Trigger: models.RuleTriggerCardMoved,
+			BoardID: targetList.BoardID,
+			CardID:  cardId,
+			ListID:  targetList.ID,
+		})
+		if err != nil {
+			tx.Rollback()
+			return "", http.StatusInternalServerError, err
+		}
+	}
+
+	tx.Commit()
+
+	return warning, http.StatusAccepted, nil
+}
+
+// MoveList moves a list with its cards to another board at the given position (at the end when 0),
+// the labels, custom field values and assignees of its cards are migrated to the target board
+func (repo ListRepository) MoveList(context models.Context, id string, targetBoardId string, position int) (int, error) {
+	var list models.List
+	err := repo.db.Where("id = ?", id).First(&list).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if list.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+	}
+	if list.BoardID == targetBoardId {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "ListAlreadyOnBoard"))
+	}
+
+	sourceBoard, severity, err := repo.getBoardWithCards(context, list.BoardID)
+	if err != nil {
+		return severity, err
+	}
+	targetBoard, severity, err := repo.getBoardWithCards(context, targetBoardId)
+	if err != nil {
 		return severity, err
 	}
+	targetRanks := []string{}
+	for _, targetList := range targetBoard.Lists {
+		targetRanks = append(targetRanks, targetList.Rank)
+	}
+
+	// all cards of the list move, archived ones included
+	cardIds := []string{}
+	err = repo.db.Model(&models.Card{}).Where("list_id = ?", list.ID).Pluck("id", &cardIds).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "board",
+		FromValue: sourceBoard.Title,
+		ToValue:   targetBoard.Title,
+	}})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.List{}).Where("id = ?", list.ID).Updates(map[string]interface{}{"board_id": targetBoard.ID, "rank_key": rank.At(targetRanks, position-1)}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// labels and custom fields are matched by name on the target board
+	mapping, err := clone.Match(tx, sourceBoard.ID, targetBoard.ID)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = clone.MoveCards(tx, cardIds, targetBoard.ID, mapping)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation on both boards
+	for _, boardId := range []string{sourceBoard.ID, targetBoard.ID} {
+		_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+			UserID:         context.UserId,
+			BoardID:        boardId,
+			Action:         "movelist",
+			ActionTargetID: list.ID,
+			Changes:        string(changesJson),
+		})
+		if err != nil {
+			tx.Rollback()
+			return severity, err
+		}
+	}
 
 	tx.Commit()
 
 	return http.StatusAccepted, nil
 }
 
+// getBoardWithCards returns a board with its open lists and cards, ordered by rank
+func (repo ListRepository) getBoardWithCards(context models.Context, boardId string) (*models.Board, int, error) {
+	var board *models.Board
+	err := repo.db.
+		Preload("Lists", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
+		}).
+		Where("id = ?", boardId).
+		First(&board).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if board == nil || board.ID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	}
+
+	return board, http.StatusOK, nil
+}
+
 func (repo ListRepository) DeleteList(context models.Context, id string) (int, error) {
 	list, severity, err := repo.GetList(context, id)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -343,15 +644,69 @@ func (repo ListRepository) DeleteList(context models.Context, id string) (int, e
 		return http.StatusInternalServerError, err
 	}
 
-	// delete comments
-	for _, card := range list.Cards {
-		for _, comment := range card.Comments {
-			err = tx.Delete(&comment).Error
-			if err != nil {
-				tx.Rollback()
-				return http.StatusInternalServerError, err
-			}
-		}
+	// delete reactions on the cards and their comments
+	err = reaction.DeleteOfCards(tx, "SELECT id FROM cards WHERE list_id = ?", list.ID)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// delete comments with their revisions
+	err = tx.Where("comment_id IN (SELECT id FROM comments WHERE card_id IN (SELECT id FROM cards WHERE list_id = ?))", list.ID).Delete(&models.CommentRevision{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where("card_id IN (SELECT id FROM cards WHERE list_id = ?)", list.ID).Delete(&models.Comment{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	listCards := "card_id IN (SELECT id FROM cards WHERE list_id = ?)"
+	// delete custom field values, labels and assignees of the cards
+	err = tx.Where(listCards, list.ID).Delete(&models.CustomFieldValue{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where(listCards, list.ID).Delete(&models.CardLabel{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where(listCards, list.ID).Delete(&models.CardAssignee{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// delete attachments of all cards (archived ones included), their data is removed once the list is gone
+	attachments := []*models.Attachment{}
+	err = tx.Where(listCards, list.ID).Find(&attachments).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	err = tx.Where(listCards, list.ID).Delete(&models.Attachment{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// delete links from and to all cards
+	err = tx.Where("card_id IN (SELECT id FROM cards WHERE list_id = ?) OR linked_card_id IN (SELECT id FROM cards WHERE list_id = ?)", list.ID, list.ID).Delete(&models.CardLink{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// delete recurrences of the cards and recurrences creating cards in the list
+	err = tx.Where("card_id IN (SELECT id FROM cards WHERE list_id = ?) OR list_id = ?", list.ID, list.ID).Delete(&models.CardRecurrence{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	// delete notifications of the cards
+	err = tx.Where("card_id IN (SELECT id FROM cards WHERE list_id = ?)", list.ID).Delete(&models.Notification{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
 	}
 	// delete cards
 	for _, card := range list.Cards {
@@ -368,49 +723,131 @@ func (repo ListRepository) DeleteList(context models.Context, id string) (int, e
 		return http.StatusInternalServerError, err
 	}
 
-	// get board from db to update lists' positions
-	boardId := list.BoardID
-	var board models.Board
-	err = tx.Where("id = ?", boardId).First(&board).Error
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        list.BoardID,
+		Action:         "deletelist",
+		ActionTargetID: list.ID,
+	})
 	if err != nil {
 		tx.Rollback()
-		return http.StatusInternalServerError, err
-	}
-	if board.ID == "" {
-		tx.Rollback()
-		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+		return severity, err
 	}
 
-	// update positions of lists
-	newPosition := 0
-	for _, loopList := range board.Lists {
-		// don't process removed record
-		if loopList.ID == id {
-			continue
-		}
-		newPosition++
-		err := tx.Model(&models.List{}).Where("id = ?", loopList.ID).Update("position", newPosition).Error
+	tx.Commit()
+
+	for _, attachment := range attachments {
+		err = repo.storage.Delete(attachment.StorageKey)
 		if err != nil {
-			tx.Rollback()
-			return http.StatusInternalServerError, err
+			repo.log.Warn("Failed to delete attachment data " + attachment.StorageKey + ": " + err.Error())
 		}
 	}
 
+	return http.StatusAccepted, nil
+}
+
+// CopyList deep-copies a list (cards, checklists with items and optionally comments) at the end of the target board
+func (repo ListRepository) CopyList(context models.Context, id string, targetBoardId string, title string, withComments bool) (string, int, error) {
+	var source models.List
+	err := repo.db.
+		Preload("Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
+		}).
+		Preload("Cards.Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		}).
+		Preload("Cards.Checklists", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("created_at DESC")
+		}).
+		Preload("Cards.Checklists.Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Cards.Labels").
+		Preload("Cards.CustomFieldValues").
+		Where("id = ?", id).
+		First(&source).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if source.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+	}
+
+	if targetBoardId == "" {
+		targetBoardId = source.BoardID
+	}
+	var board models.Board
+	err = repo.db.
+		Where("id = ?", targetBoardId).
+		First(&board).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if board.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	}
+	// the copy goes at the end of the target board, cards keep their ranks
+	lastRank, err := lastListRank(repo.db, board.ID)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	if title != "" {
+		source.Title = title
+	}
+
+	tx := repo.db.Begin()
+
+	// labels and custom fields are kept on the same board, matched by name on another board
+	mapping, err := clone.Match(tx, source.BoardID, board.ID)
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+	list, err := clone.List(tx, &source, board.ID, rank.Between(lastRank, ""), withComments, mapping)
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
 	// log operation
-	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "copiedfrom",
+		FromValue: source.ID,
+		ToValue:   list.ID,
+	}})
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
 		UserID:         context.UserId,
-		BoardID:        list.BoardID,
-		Action:         "deletelist",
+		BoardID:        board.ID,
+		Action:         "copylist",
 		ActionTargetID: list.ID,
+		Changes:        string(changesJson),
 	})
 	if err != nil {
 		tx.Rollback()
-		return severity, err
+		return "", severity, err
 	}
 
 	tx.Commit()
 
-	return http.StatusAccepted, nil
+	return list.ID, http.StatusCreated, nil
+}
+
+// lastListRank returns the highest rank of the lists of a board, archived ones included so that they can be restored
+// at their place
+func lastListRank(db *gorm.DB, boardId string) (string, error) {
+	var lastRank *string
+	err := db.Model(&models.List{}).Where("board_id = ?", boardId).Select("MAX(rank_key)").Row().Scan(&lastRank)
+	if err != nil || lastRank == nil {
+		return "", err
+	}
+
+	return *lastRank, nil
 }
 
 func whatChanged(listBefore *models.List, listAfter *models.List) ([]*models.LogChange, error) {
@@ -423,11 +860,32 @@ func whatChanged(listBefore *models.List, listAfter *models.List) ([]*models.Log
 			ToValue:   listAfter.Title,
 		})
 	}
-	if listBefore.Position != listAfter.Position {
+	if listBefore.IsDone != listAfter.IsDone {
+		changes = append(changes, &models.LogChange{
+			Field:     "isDone",
+			FromValue: strconv.FormatBool(listBefore.IsDone),
+			ToValue:   strconv.FormatBool(listAfter.IsDone),
+		})
+	}
+	if listBefore.RefuseBlocked != listAfter.RefuseBlocked {
+		changes = append(changes, &models.LogChange{
+			Field:     "refuseBlocked",
+			FromValue: strconv.FormatBool(listBefore.RefuseBlocked),
+			ToValue:   strconv.FormatBool(listAfter.RefuseBlocked),
+		})
+	}
+	if listBefore.WipLimit != listAfter.WipLimit {
+		changes = append(changes, &models.LogChange{
+			Field:     "wipLimit",
+			FromValue: strconv.Itoa(listBefore.WipLimit),
+			ToValue:   strconv.Itoa(listAfter.WipLimit),
+		})
+	}
+	if listBefore.WipHard != listAfter.WipHard {
 		changes = append(changes, &models.LogChange{
-			Field:     "position",
-			FromValue: strconv.Itoa(listBefore.Position),
-			ToValue:   strconv.Itoa(listAfter.Position),
+			Field:     "wipHard",
+			FromValue: strconv.FormatBool(listBefore.WipHard),
+			ToValue:   strconv.FormatBool(listAfter.WipHard),
 		})
 	}
 
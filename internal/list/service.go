@@ -1,47 +1,134 @@
 package list
 
-import "trellode-go/internal/models"
+import (
+	"trellode-go/internal/member"
+	"trellode-go/internal/models"
+)
 
 type ListServiceInterface interface {
 	GetList(models.Context, string) (*models.List, int, error)
 	CreateList(models.Context, *models.List) (string, int, error)
 	UpdateList(models.Context, *models.List) (int, error)
 	UpdateCardsOrder(models.Context, string, string) (int, error)
-	MoveCardToList(models.Context, int, int, string, int) (int, error)
+	MoveCardToList(models.Context, string, int, int, string, int) (string, int, error)
+	MoveCard(models.Context, string, string, int, string, int) (string, int, error)
+	MoveList(models.Context, string, string, int) (int, error)
 	DeleteList(models.Context, string) (int, error)
+	CopyList(models.Context, string, string, string, bool) (string, int, error)
 }
 
 type ListService struct {
-	repo ListRepositoryInterface
+	repo          ListRepositoryInterface
+	memberService member.MemberService
 }
 
 // NewPersonService returns a service to manipulate unit
-func NewListService(repo ListRepositoryInterface) ListService {
+func NewListService(repo ListRepositoryInterface, memberService member.MemberService) ListService {
 	return ListService{
-		repo: repo,
+		repo:          repo,
+		memberService: memberService,
 	}
 }
 
 func (p ListService) GetList(context models.Context, id string) (*models.List, int, error) {
+	severity, err := p.memberService.CheckListRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
 	return p.repo.GetList(context, id)
 }
 
 func (p ListService) CreateList(context models.Context, board *models.List) (string, int, error) {
+	severity, err := p.memberService.CheckBoardRole(context, board.BoardID, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
 	return p.repo.CreateList(context, board)
 }
 
 func (p ListService) UpdateList(context models.Context, board *models.List) (int, error) {
+	severity, err := p.memberService.CheckListRole(context, board.ID, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.UpdateList(context, board)
 }
 
 func (p ListService) UpdateCardsOrder(context models.Context, listId string, idsOrdered string) (int, error) {
+	severity, err := p.memberService.CheckListRole(context, listId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.UpdateCardsOrder(context, listId, idsOrdered)
 }
 
-func (p ListService) MoveCardToList(context models.Context, sourceListIndex int, sourceCardIndex int, targetListId string, targetCardIndex int) (int, error) {
-	return p.repo.MoveCardToList(context, sourceListIndex, sourceCardIndex, targetListId, targetCardIndex)
+func (p ListService) MoveCardToList(context models.Context, sourceBoardId string, sourceListIndex int, sourceCardIndex int, targetListId string, targetCardIndex int) (string, int, error) {
+	severity, err := p.memberService.CheckListRole(context, targetListId, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+	if sourceBoardId != "" {
+		severity, err = p.memberService.CheckBoardRole(context, sourceBoardId, models.BoardRoleEditor)
+		if err != nil {
+			return "", severity, err
+		}
+	}
+
+	return p.repo.MoveCardToList(context, sourceBoardId, sourceListIndex, sourceCardIndex, targetListId, targetCardIndex)
+}
+
+func (p ListService) MoveCard(context models.Context, cardId string, fromListId string, version int, toListId string, position int) (string, int, error) {
+	severity, err := p.memberService.CheckCardRole(context, cardId, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+	severity, err = p.memberService.CheckListRole(context, toListId, models.BoardRoleEditor)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return p.repo.MoveCard(context, cardId, fromListId, version, toListId, position)
+}
+
+func (p ListService) MoveList(context models.Context, id string, targetBoardId string, position int) (int, error) {
+	severity, err := p.memberService.CheckListRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+	severity, err = p.memberService.CheckBoardRole(context, targetBoardId, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
+	return p.repo.MoveList(context, id, targetBoardId, position)
 }
 
 func (p ListService) DeleteList(context models.Context, id string) (int, error) {
+	severity, err := p.memberService.CheckListRole(context, id, models.BoardRoleEditor)
+	if err != nil {
+		return severity, err
+	}
+
 	return p.repo.DeleteList(context, id)
 }
+
+func (p ListService) CopyList(context models.Context, id string, targetBoardId string, title string, withComments bool) (string, int, error) {
+	severity, err := p.memberService.CheckListRole(context, id, models.BoardRoleViewer)
+	if err != nil {
+		return "", severity, err
+	}
+	if targetBoardId != "" {
+		severity, err = p.memberService.CheckBoardRole(context, targetBoardId, models.BoardRoleEditor)
+	} else {
+		severity, err = p.memberService.CheckListRole(context, id, models.BoardRoleEditor)
+	}
+	if err != nil {
+		return "", severity, err
+	}
+
+	return p.repo.CopyList(context, id, targetBoardId, title, withComments)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"trellode-go/internal/member/access"
 	"trellode-go/internal/models"
 
 	"github.com/google/uuid"
@@ -19,6 +20,7 @@ type LogRepository struct {
 type LogRepositoryInterface interface {
 	GetLogs(models.Context, string) ([]*models.Log, int, error)
 	CreateLog(models.Context, *gorm.DB, *models.Log) (string, int, error)
+	IsBoardMember(models.Context, string) (bool, error)
 }
 
 func NewLogRepository(db *gorm.DB, log *zap.Logger) LogRepository {
@@ -31,9 +33,14 @@ func NewLogRepository(db *gorm.DB, log *zap.Logger) LogRepository {
 func (repo LogRepository) GetLogs(context models.Context, boardId string) ([]*models.Log, int, error) {
 	logs := []*models.Log{}
 
-	err := repo.db.
-		Preload("User").
-		Where("user_id = ? AND board_id = ?", context.UserId, boardId).
+	// logs not related to a board are only visible to their author, board logs to all members
+	query := repo.db.Preload("User")
+	if boardId == "" {
+		query = query.Where("user_id = ? AND board_id = ?", context.UserId, boardId)
+	} else {
+		query = query.Where("board_id = ?", boardId)
+	}
+	err := query.
 		Order("created_at DESC").
 		Find(&logs).Error
 	if err != nil {
@@ -72,6 +79,66 @@ func (repo LogRepository) GetLogs(context models.Context, boardId string) ([]*mo
 				log.ActionTargetTitle = board.Title
 			}
 		}
+		if strings.HasSuffix(log.Action, "customfield") {
+			var field *models.CustomField
+			err := repo.db.Where("id = ?", log.ActionTargetID).First(&field).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, http.StatusInternalServerError, err
+			}
+			if field.ID != "" {
+				log.ActionTargetTitle = field.Name
+			}
+		}
+		if strings.HasSuffix(log.Action, "label") {
+			var label *models.Label
+			err := repo.db.Where("id = ?", log.ActionTargetID).First(&label).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, http.StatusInternalServerError, err
+			}
+			if label.ID != "" {
+				log.ActionTargetTitle = label.Name
+			}
+		}
+		if strings.HasSuffix(log.Action, "attachment") {
+			var attachment *models.Attachment
+			err := repo.db.Where("id = ?", log.ActionTargetID).First(&attachment).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, http.StatusInternalServerError, err
+			}
+			if attachment.ID != "" {
+				log.ActionTargetTitle = attachment.Name
+			}
+		}
+		if strings.HasSuffix(log.Action, "workspace") {
+			var workspace *models.Workspace
+			err := repo.db.Where("id = ?", log.ActionTargetID).First(&workspace).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, http.StatusInternalServerError, err
+			}
+			if workspace.ID != "" {
+				log.ActionTargetTitle = workspace.Name
+			}
+		}
+		if strings.HasSuffix(log.Action, "rule") {
+			var rule *models.Rule
+			err := repo.db.Where("id = ?", log.ActionTargetID).First(&rule).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, http.StatusInternalServerError, err
+			}
+			if rule.ID != "" {
+				log.ActionTargetTitle = rule.Name
+			}
+		}
+		if strings.HasSuffix(log.Action, "member") {
+			var user *models.User
+			err := repo.db.Where("id = ?", log.ActionTargetID).First(&user).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, http.StatusInternalServerError, err
+			}
+			if user.ID != "" {
+				log.ActionTargetTitle = user.Firstname + " " + user.Lastname
+			}
+		}
 	}
 
 	return logs, http.StatusOK, nil
@@ -89,3 +156,8 @@ func (repo LogRepository) CreateLog(context models.Context, tx *gorm.DB, log *mo
 
 	return log.ID, http.StatusCreated, nil
 }
+
+// IsBoardMember tells if the current user has a role on the board
+func (repo LogRepository) IsBoardMember(context models.Context, boardId string) (bool, error) {
+	return access.IsBoardUser(repo.db, boardId, context.UserId)
+}
@@ -1,7 +1,10 @@
 package log
 
 import (
+	"errors"
+	"net/http"
 	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
 
 	"gorm.io/gorm"
 )
@@ -23,6 +26,17 @@ func NewLogService(repo LogRepositoryInterface) LogService {
 }
 
 func (s LogService) GetLogs(context models.Context, boardId string) ([]*models.Log, int, error) {
+	// member services depend on logs, so membership is checked here directly
+	if boardId != "" {
+		isMember, err := s.repo.IsBoardMember(context, boardId)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if !isMember {
+			return nil, http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "InsufficientRole"))
+		}
+	}
+
 	return s.repo.GetLogs(context, boardId)
 }
 
@@ -0,0 +1,53 @@
+// Package access selects the users who can see a board and the boards a user can see, in SQL.
+// It is kept apart from the member package so that packages member depends on, such as log, can use it.
+package access
+
+import (
+	"trellode-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// the users who get a role on a board, as member.GetRole gives them: its creator, its members, the admins of its workspace
+// and the other workspace members when the workspace has a default role
+const boardUsersSQL = "SELECT user_id FROM boards WHERE id = @board" +
+	" UNION SELECT user_id FROM board_members WHERE board_id = @board" +
+	" UNION SELECT workspace_members.user_id FROM workspace_members" +
+	" JOIN workspaces ON workspaces.id = workspace_members.workspace_id" +
+	" JOIN boards ON boards.workspace_id = workspaces.id" +
+	" WHERE boards.id = @board AND (workspace_members.role = @admin OR workspaces.default_role <> '')"
+
+// the boards on which a user gets a role, following the same rule
+const visibleBoardsSQL = "SELECT id FROM boards WHERE user_id = @user" +
+	" UNION SELECT board_id FROM board_members WHERE user_id = @user" +
+	" UNION SELECT boards.id FROM boards" +
+	" JOIN workspaces ON workspaces.id = boards.workspace_id" +
+	" JOIN workspace_members ON workspace_members.workspace_id = workspaces.id" +
+	" WHERE workspace_members.user_id = @user AND (workspace_members.role = @admin OR workspaces.default_role <> '')"
+
+// BoardUsers returns a subquery selecting the ids of the users who have a role on the board boardId,
+// to be used as "user_id IN (?)"
+func BoardUsers(db *gorm.DB, boardId string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Raw(boardUsersSQL, map[string]interface{}{"board": boardId, "admin": models.WorkspaceRoleAdmin})
+}
+
+// VisibleBoards returns a subquery selecting the ids of the boards on which the user userId has a role,
+// to be used as "board_id IN (?)"
+func VisibleBoards(db *gorm.DB, userId string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Raw(visibleBoardsSQL, map[string]interface{}{"user": userId, "admin": models.WorkspaceRoleAdmin})
+}
+
+// IsBoardUser tells if the user userId has a role on the board boardId
+func IsBoardUser(db *gorm.DB, boardId string, userId string) (bool, error) {
+	var count int64
+	err := db.Model(&models.User{}).
+		Where("id = ? AND id IN (?)", userId, BoardUsers(db, boardId)).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,45 @@
+package access
+
+import (
+	"testing"
+	"trellode-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// the queries are only built, no database is needed
+func dryRun(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	assert.NoError(t, err)
+	return db
+}
+
+func TestBoardUsers(t *testing.T) {
+	db := dryRun(t)
+
+	stmt := db.Where("id = ? AND id IN (?)", "user", BoardUsers(db, "board")).Find(&[]models.User{}).Statement
+	assert.Equal(t, "SELECT * FROM `users` WHERE id = ? AND id IN ("+
+		"SELECT user_id FROM boards WHERE id = ?"+
+		" UNION SELECT user_id FROM board_members WHERE board_id = ?"+
+		" UNION SELECT workspace_members.user_id FROM workspace_members"+
+		" JOIN workspaces ON workspaces.id = workspace_members.workspace_id"+
+		" JOIN boards ON boards.workspace_id = workspaces.id"+
+		" WHERE boards.id = ? AND (workspace_members.role = ? OR workspaces.default_role <> ''))", stmt.SQL.String())
+	assert.Equal(t, []interface{}{"user", "board", "board", "board", models.WorkspaceRoleAdmin}, stmt.Vars)
+}
+
+func TestVisibleBoards(t *testing.T) {
+	db := dryRun(t)
+
+	stmt := db.Where("user_id = ? AND board_id IN (?)", "user", VisibleBoards(db, "user")).Find(&[]models.Notification{}).Statement
+	assert.Equal(t, "SELECT * FROM `notifications` WHERE user_id = ? AND board_id IN ("+
+		"SELECT id FROM boards WHERE user_id = ?"+
+		" UNION SELECT board_id FROM board_members WHERE user_id = ?"+
+		" UNION SELECT boards.id FROM boards"+
+		" JOIN workspaces ON workspaces.id = boards.workspace_id"+
+		" JOIN workspace_members ON workspace_members.workspace_id = workspaces.id"+
+		" WHERE workspace_members.user_id = ? AND (workspace_members.role = ? OR workspaces.default_role <> ''))", stmt.SQL.String())
+	assert.Equal(t, []interface{}{"user", "user", "user", "user", models.WorkspaceRoleAdmin}, stmt.Vars)
+}
@@ -0,0 +1,441 @@
+package member
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"trellode-go/internal/log"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MemberRepository struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	logService log.LogService
+}
+
+type MemberRepositoryInterface interface {
+	GetMembers(models.Context, string) ([]*models.BoardMember, int, error)
+	GetMember(models.Context, string, string) (*models.BoardMember, int, error)
+	CreateMember(models.Context, *models.BoardMember) (int, error)
+	UpdateMember(models.Context, *models.BoardMember) (int, error)
+	DeleteMember(models.Context, string, string) (int, error)
+	GetRole(models.Context, string) (string, int, error)
+
+	GetBoardIdOfList(models.Context, string) (string, int, error)
+	GetBoardIdOfCard(models.Context, string) (string, int, error)
+	GetBoardIdOfChecklist(models.Context, string) (string, int, error)
+	GetBoardIdOfChecklistItem(models.Context, string) (string, int, error)
+	GetBoardIdOfComment(models.Context, string) (string, int, error)
+	GetBoardIdOfLabel(models.Context, string) (string, int, error)
+	GetBoardIdOfCustomField(models.Context, string) (string, int, error)
+	GetBoardIdOfAttachment(models.Context, string) (string, int, error)
+	GetBoardIdOfRule(models.Context, string) (string, int, error)
+}
+
+func NewMemberRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) MemberRepository {
+	return MemberRepository{
+		db:         db,
+		log:        log,
+		logService: logService,
+	}
+}
+
+func (repo MemberRepository) GetMembers(context models.Context, boardId string) ([]*models.BoardMember, int, error) {
+	members := []*models.BoardMember{}
+	err := repo.db.
+		Preload("User").
+		Where("board_id = ?", boardId).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return members, http.StatusOK, nil
+}
+
+func (repo MemberRepository) GetMember(context models.Context, boardId string, userId string) (*models.BoardMember, int, error) {
+	var member models.BoardMember
+	err := repo.db.
+		Preload("User").
+		Where("board_id = ? AND user_id = ?", boardId, userId).
+		First(&member).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if member.UserID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "MemberNotFound"))
+	}
+
+	return &member, http.StatusOK, nil
+}
+
+func (repo MemberRepository) CreateMember(context models.Context, member *models.BoardMember) (int, error) {
+	if !IsValidRole(member.Role) {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidRole"))
+	}
+
+	// resolve user from email if no id was given
+	var user models.User
+	var err error
+	if member.UserID != "" {
+		err = repo.db.Where("id = ?", member.UserID).First(&user).Error
+	} else {
+		err = repo.db.Where("email = ?", member.Email).First(&user).Error
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if user.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "UserNotFound"))
+	}
+	member.UserID = user.ID
+
+	// check user is not already a member
+	var existingMember models.BoardMember
+	err = repo.db.Where("board_id = ? AND user_id = ?", member.BoardID, member.UserID).First(&existingMember).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if existingMember.UserID != "" {
+		return http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "MemberAlreadyExists"))
+	}
+
+	member.User = nil
+
+	tx := repo.db.Begin()
+
+	err = tx.Create(&member).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        member.BoardID,
+		Action:         "addmember",
+		ActionTargetID: member.UserID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusCreated, nil
+}
+
+func (repo MemberRepository) UpdateMember(context models.Context, member *models.BoardMember) (int, error) {
+	if !IsValidRole(member.Role) {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidRole"))
+	}
+
+	memberBefore, severity, err := repo.GetMember(context, member.BoardID, member.UserID)
+	if err != nil {
+		return severity, err
+	}
+
+	// the creator of a board always stays owner
+	isCreator, err := repo.isBoardCreator(member.BoardID, member.UserID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if isCreator && member.Role != models.BoardRoleOwner {
+		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "BoardCreatorRole"))
+	}
+
+	changes := []*models.LogChange{}
+	if memberBefore.Role != member.Role {
+		changes = append(changes, &models.LogChange{
+			Field:     "role",
+			FromValue: memberBefore.Role,
+			ToValue:   member.Role,
+		})
+	}
+	// marshal changes to JSON string
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.BoardMember{}).
+		Where("board_id = ? AND user_id = ?", member.BoardID, member.UserID).
+		Updates(map[string]interface{}{"role": member.Role, "updated_at": time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        member.BoardID,
+		Action:         "updatemember",
+		ActionTargetID: member.UserID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+func (repo MemberRepository) DeleteMember(context models.Context, boardId string, userId string) (int, error) {
+	_, severity, err := repo.GetMember(context, boardId, userId)
+	if err != nil {
+		return severity, err
+	}
+
+	// the creator of a board cannot be removed from it
+	isCreator, err := repo.isBoardCreator(boardId, userId)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if isCreator {
+		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "BoardCreatorRole"))
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Where("board_id = ? AND user_id = ?", boardId, userId).Delete(&models.BoardMember{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         "removemember",
+		ActionTargetID: userId,
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// GetRole returns the role of the current user on a board, or an empty string if the user is not a member.
+// The creator of a board (Board.UserID) is always considered owner, members of the board workspace get
+// the best of their own role and the one given by the workspace. access.BoardUsers and access.VisibleBoards follow the same rule.
+func (repo MemberRepository) GetRole(context models.Context, boardId string) (string, int, error) {
+	var board models.Board
+	err := repo.db.Where("id = ?", boardId).First(&board).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if board.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	}
+	if board.UserID == context.UserId {
+		return models.BoardRoleOwner, http.StatusOK, nil
+	}
+
+	var member models.BoardMember
+	err = repo.db.Where("board_id = ? AND user_id = ?", boardId, context.UserId).First(&member).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if board.WorkspaceID == nil {
+		return member.Role, http.StatusOK, nil
+	}
+
+	// workspace admins own its boards, other workspace members get the workspace default role
+	workspaceRole, err := repo.getWorkspaceBoardRole(*board.WorkspaceID, context.UserId)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	if roleLevel(workspaceRole) > roleLevel(member.Role) {
+		return workspaceRole, http.StatusOK, nil
+	}
+
+	return member.Role, http.StatusOK, nil
+}
+
+// getWorkspaceBoardRole returns the board role a user gets through his membership of a workspace
+func (repo MemberRepository) getWorkspaceBoardRole(workspaceId string, userId string) (string, error) {
+	var workspaceMember models.WorkspaceMember
+	err := repo.db.Where("workspace_id = ? AND user_id = ?", workspaceId, userId).First(&workspaceMember).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if workspaceMember.UserID == "" {
+		return "", nil
+	}
+	if workspaceMember.Role == models.WorkspaceRoleAdmin {
+		return models.BoardRoleOwner, nil
+	}
+
+	var workspace models.Workspace
+	err = repo.db.Where("id = ?", workspaceId).First(&workspace).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+
+	return workspace.DefaultRole, nil
+}
+
+func (repo MemberRepository) GetBoardIdOfList(context models.Context, listId string) (string, int, error) {
+	var list models.List
+	err := repo.db.Where("id = ?", listId).First(&list).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if list.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ListNotFound"))
+	}
+
+	return list.BoardID, http.StatusOK, nil
+}
+
+func (repo MemberRepository) GetBoardIdOfCard(context models.Context, cardId string) (string, int, error) {
+	var card models.Card
+	err := repo.db.Where("id = ?", cardId).First(&card).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if card.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CardNotFound"))
+	}
+
+	return repo.GetBoardIdOfList(context, card.ListID)
+}
+
+func (repo MemberRepository) GetBoardIdOfChecklist(context models.Context, checklistId string) (string, int, error) {
+	var checklist models.Checklist
+	err := repo.db.Where("id = ?", checklistId).First(&checklist).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if checklist.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ChecklistNotFound"))
+	}
+
+	return repo.GetBoardIdOfCard(context, checklist.CardID)
+}
+
+func (repo MemberRepository) GetBoardIdOfChecklistItem(context models.Context, checklistItemId string) (string, int, error) {
+	var checklistItem models.ChecklistItem
+	err := repo.db.Where("id = ?", checklistItemId).First(&checklistItem).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if checklistItem.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "ChecklistItemNotFound"))
+	}
+
+	return repo.GetBoardIdOfChecklist(context, checklistItem.ChecklistID)
+}
+
+func (repo MemberRepository) GetBoardIdOfComment(context models.Context, commentId string) (string, int, error) {
+	var comment models.Comment
+	err := repo.db.Where("id = ?", commentId).First(&comment).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if comment.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CommentNotFound"))
+	}
+
+	return repo.GetBoardIdOfCard(context, comment.CardID)
+}
+
+func (repo MemberRepository) GetBoardIdOfLabel(context models.Context, labelId string) (string, int, error) {
+	var label models.Label
+	err := repo.db.Where("id = ?", labelId).First(&label).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if label.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "LabelNotFound"))
+	}
+
+	return label.BoardID, http.StatusOK, nil
+}
+
+func (repo MemberRepository) GetBoardIdOfCustomField(context models.Context, customFieldId string) (string, int, error) {
+	var field models.CustomField
+	err := repo.db.Where("id = ?", customFieldId).First(&field).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if field.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "CustomFieldNotFound"))
+	}
+
+	return field.BoardID, http.StatusOK, nil
+}
+
+func (repo MemberRepository) GetBoardIdOfAttachment(context models.Context, attachmentId string) (string, int, error) {
+	var attachment models.Attachment
+	err := repo.db.Where("id = ?", attachmentId).First(&attachment).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if attachment.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "AttachmentNotFound"))
+	}
+
+	return repo.GetBoardIdOfCard(context, attachment.CardID)
+}
+
+func (repo MemberRepository) GetBoardIdOfRule(context models.Context, ruleId string) (string, int, error) {
+	var rule models.Rule
+	err := repo.db.Where("id = ?", ruleId).First(&rule).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if rule.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "RuleNotFound"))
+	}
+
+	return rule.BoardID, http.StatusOK, nil
+}
+
+func (repo MemberRepository) isBoardCreator(boardId string, userId string) (bool, error) {
+	var board models.Board
+	err := repo.db.Where("id = ?", boardId).First(&board).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	return board.ID != "" && board.UserID == userId, nil
+}
+
+// IsValidRole tells if the given role is one of owner, editor or viewer
+func IsValidRole(role string) bool {
+	return roleLevel(role) > 0
+}
+
+// roleLevel returns a comparable level for a role, 0 meaning no access
+func roleLevel(role string) int {
+	switch role {
+	case models.BoardRoleOwner:
+		return 3
+	case models.BoardRoleEditor:
+		return 2
+	case models.BoardRoleViewer:
+		return 1
+	}
+	return 0
+}
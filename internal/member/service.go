@@ -0,0 +1,172 @@
+package member
+
+import (
+	"errors"
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+)
+
+type MemberServiceInterface interface {
+	GetMembers(models.Context, string) ([]*models.BoardMember, int, error)
+	CreateMember(models.Context, *models.BoardMember) (int, error)
+	UpdateMember(models.Context, *models.BoardMember) (int, error)
+	DeleteMember(models.Context, string, string) (int, error)
+
+	CheckBoardRole(models.Context, string, string) (int, error)
+	CheckListRole(models.Context, string, string) (int, error)
+	CheckCardRole(models.Context, string, string) (int, error)
+	CheckChecklistRole(models.Context, string, string) (int, error)
+	CheckChecklistItemRole(models.Context, string, string) (int, error)
+	CheckCommentRole(models.Context, string, string) (int, error)
+	CheckLabelRole(models.Context, string, string) (int, error)
+	CheckCustomFieldRole(models.Context, string, string) (int, error)
+	CheckAttachmentRole(models.Context, string, string) (int, error)
+	CheckRuleRole(models.Context, string, string) (int, error)
+}
+
+type MemberService struct {
+	repo MemberRepositoryInterface
+}
+
+// NewMemberService returns a service to manipulate board members
+func NewMemberService(repo MemberRepositoryInterface) MemberService {
+	return MemberService{
+		repo: repo,
+	}
+}
+
+func (s MemberService) GetMembers(context models.Context, boardId string) ([]*models.BoardMember, int, error) {
+	severity, err := s.CheckBoardRole(context, boardId, models.BoardRoleViewer)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.GetMembers(context, boardId)
+}
+
+func (s MemberService) CreateMember(context models.Context, member *models.BoardMember) (int, error) {
+	severity, err := s.CheckBoardRole(context, member.BoardID, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.CreateMember(context, member)
+}
+
+func (s MemberService) UpdateMember(context models.Context, member *models.BoardMember) (int, error) {
+	severity, err := s.CheckBoardRole(context, member.BoardID, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.UpdateMember(context, member)
+}
+
+func (s MemberService) DeleteMember(context models.Context, boardId string, userId string) (int, error) {
+	// any member can leave a board, only owners can remove someone else
+	minimumRole := models.BoardRoleOwner
+	if userId == context.UserId {
+		minimumRole = models.BoardRoleViewer
+	}
+	severity, err := s.CheckBoardRole(context, boardId, minimumRole)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.DeleteMember(context, boardId, userId)
+}
+
+// CheckBoardRole returns an error if the current user does not have at least minimumRole on the board
+func (s MemberService) CheckBoardRole(context models.Context, boardId string, minimumRole string) (int, error) {
+	role, severity, err := s.repo.GetRole(context, boardId)
+	if err != nil {
+		return severity, err
+	}
+	if roleLevel(role) < roleLevel(minimumRole) {
+		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "InsufficientRole"))
+	}
+
+	return http.StatusOK, nil
+}
+
+func (s MemberService) CheckListRole(context models.Context, listId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfList(context, listId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckCardRole(context models.Context, cardId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfCard(context, cardId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckChecklistRole(context models.Context, checklistId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfChecklist(context, checklistId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckChecklistItemRole(context models.Context, checklistItemId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfChecklistItem(context, checklistItemId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckCommentRole(context models.Context, commentId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfComment(context, commentId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckLabelRole(context models.Context, labelId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfLabel(context, labelId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckCustomFieldRole(context models.Context, customFieldId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfCustomField(context, customFieldId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckAttachmentRole(context models.Context, attachmentId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfAttachment(context, attachmentId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
+
+func (s MemberService) CheckRuleRole(context models.Context, ruleId string, minimumRole string) (int, error) {
+	boardId, severity, err := s.repo.GetBoardIdOfRule(context, ruleId)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.CheckBoardRole(context, boardId, minimumRole)
+}
@@ -10,6 +10,7 @@ func CorsMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "*")
 		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "*")
+		ctx.Writer.Header().Set("Access-Control-Expose-Headers", "X-Warning, X-Next-Cursor")
 		ctx.Next()
 	}
 }
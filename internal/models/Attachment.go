@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	ID          string    `gorm:"column:id;primaryKey" json:"id"`
+	CardID      string    `gorm:"column:card_id" json:"cardId"`
+	UserID      string    `gorm:"column:user_id" json:"userId"`
+	Name        string    `gorm:"column:name" json:"name"`
+	ContentType string    `gorm:"column:content_type" json:"contentType"`
+	Size        int64     `gorm:"column:size" json:"size"`
+	StorageKey  string    `gorm:"column:storage_key" json:"-"`
+	CreatedAt   time.Time `gorm:"created_at" json:"createdAt"`
+}
+
+func (Attachment) TableName() string {
+	return "attachments"
+}
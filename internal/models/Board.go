@@ -3,19 +3,23 @@ package models
 import "time"
 
 type Board struct {
-	ID             string      `gorm:"column:id;primaryKey" json:"id"`
-	UserID         string      `gorm:"column:user_id" json:"userId"`
-	Title          string      `gorm:"column:title" json:"title"`
-	BackgroundID   string      `gorm:"column:background_id" json:"backgroundId"`
-	Background     *Background `gorm:"foreignKey:BackgroundID" json:"background"`
-	MenuColorLight string      `gorm:"-" json:"menuColorLight"`
-	MenuColorDark  string      `gorm:"-" json:"menuColorDark"`
-	ListColor      string      `gorm:"-" json:"listColor"`
-	Lists          []List      `gorm:"foreignKey:BoardID" json:"lists"`
-	CreatedAt      time.Time   `gorm:"created_at" json:"createdAt"`
-	UpdatedAt      time.Time   `gorm:"updated_at" json:"updatedAt"`
-	ArchivedAt     *time.Time  `gorm:"archived_at" json:"archivedAt"`
-	OpenedAt       time.Time   `gorm:"opened_at" json:"openedAt"`
+	ID             string        `gorm:"column:id;primaryKey" json:"id"`
+	UserID         string        `gorm:"column:user_id" json:"userId"`
+	WorkspaceID    *string       `gorm:"column:workspace_id" json:"workspaceId"`
+	Title          string        `gorm:"column:title" json:"title"`
+	BackgroundID   string        `gorm:"column:background_id" json:"backgroundId"`
+	Background     *Background   `gorm:"foreignKey:BackgroundID" json:"background"`
+	MenuColorLight string        `gorm:"-" json:"menuColorLight"`
+	MenuColorDark  string        `gorm:"-" json:"menuColorDark"`
+	ListColor      string        `gorm:"-" json:"listColor"`
+	IsTemplate     bool          `gorm:"column:is_template" json:"isTemplate"`
+	Lists          []List        `gorm:"foreignKey:BoardID" json:"lists"`
+	Labels         []Label       `gorm:"foreignKey:BoardID" json:"labels"`
+	CustomFields   []CustomField `gorm:"foreignKey:BoardID" json:"customFields"`
+	CreatedAt      time.Time     `gorm:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time     `gorm:"updated_at" json:"updatedAt"`
+	ArchivedAt     *time.Time    `gorm:"archived_at" json:"archivedAt"`
+	OpenedAt       time.Time     `gorm:"opened_at" json:"openedAt"`
 }
 
 func (Board) TableName() string {
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// BoardExportVersion is the version of the export document produced by this API
+const BoardExportVersion = 1
+
+// BoardExport is a portable document containing a whole board, used for backups and to move boards between instances
+type BoardExport struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Board      *Board    `json:"board"`
+	Logs       []*Log    `json:"logs"`
+	Users      []*User   `json:"users"` // authors of comments and logs
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	BoardRoleOwner  = "owner"
+	BoardRoleEditor = "editor"
+	BoardRoleViewer = "viewer"
+)
+
+type BoardMember struct {
+	BoardID   string    `gorm:"column:board_id;primaryKey" json:"boardId"`
+	UserID    string    `gorm:"column:user_id;primaryKey" json:"userId"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user"`
+	Email     string    `gorm:"-" json:"email"` // used to invite a user by email
+	Role      string    `gorm:"column:role" json:"role"`
+	CreatedAt time.Time `gorm:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (BoardMember) TableName() string {
+	return "board_members"
+}
@@ -3,16 +3,31 @@ package models
 import "time"
 
 type Card struct {
-	ID          string      `gorm:"column:id;primaryKey" json:"id"`
-	ListID      string      `gorm:"column:list_id" json:"listId"`
-	Title       string      `gorm:"column:title" json:"title"`
-	Description string      `gorm:"column:description" json:"description"`
-	Position    int         `gorm:"column:position" json:"position"`
-	Comments    []Comment   `gorm:"foreignKey:CardID" json:"comments"`
-	Checklists  []Checklist `gorm:"foreignKey:CardID" json:"checklists"`
-	CreatedAt   time.Time   `gorm:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time   `gorm:"updated_at" json:"updatedAt"`
-	ArchivedAt  *time.Time  `gorm:"archived_at" json:"archivedAt"`
+	ID                string             `gorm:"column:id;primaryKey" json:"id"`
+	ListID            string             `gorm:"column:list_id" json:"listId"`
+	Title             string             `gorm:"column:title" json:"title"`
+	Description       string             `gorm:"column:description" json:"description"`
+	Rank              string             `gorm:"column:rank_key" json:"rank"`
+	Position          int                `gorm:"-" json:"position"`             // from 1, calculated from the ranks when the card is loaded with its siblings
+	Version           int                `gorm:"column:version" json:"version"` // incremented on each update and move
+	Comments          []Comment          `gorm:"foreignKey:CardID" json:"comments"`
+	CommentCount      int                `gorm:"-" json:"commentCount"` // comments not deleted, replies included, set in the board, list and card payloads which have no comments
+	Checklists        []Checklist        `gorm:"foreignKey:CardID" json:"checklists"`
+	Labels            []Label            `gorm:"many2many:card_labels" json:"labels"`
+	Assignees         []User             `gorm:"many2many:card_assignees" json:"assignees"`
+	Attachments       []Attachment       `gorm:"foreignKey:CardID" json:"attachments"`
+	Links             []CardLink         `gorm:"foreignKey:CardID" json:"links"`              // links from this card (this card blocks, relates to, duplicates...)
+	LinkedFrom        []CardLink         `gorm:"foreignKey:LinkedCardID" json:"linkedFrom"`   // links to this card (this card is blocked by...)
+	Cover             CardCover          `gorm:"embedded;embeddedPrefix:cover_" json:"cover"` // set through its own endpoint, left untouched on update
+	CustomFieldValues []CustomFieldValue `gorm:"foreignKey:CardID" json:"customFieldValues"`  // nil when not sent on update, values are then left untouched
+	Recurrence        *CardRecurrence    `gorm:"foreignKey:CardID" json:"recurrence"`         // set through its own endpoint, left untouched on create and update
+	Reactions         []ReactionSummary  `gorm:"-" json:"reactions"`
+	StartAt           *time.Time         `gorm:"column:start_at" json:"startAt"`
+	DueAt             *time.Time         `gorm:"column:due_at" json:"dueAt"`
+	CompletedAt       *time.Time         `gorm:"column:completed_at" json:"completedAt"`
+	CreatedAt         time.Time          `gorm:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time          `gorm:"updated_at" json:"updatedAt"`
+	ArchivedAt        *time.Time         `gorm:"archived_at" json:"archivedAt"`
 }
 
 func (Card) TableName() string {
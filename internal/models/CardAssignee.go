@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type CardAssignee struct {
+	CardID    string    `gorm:"column:card_id;primaryKey" json:"cardId"`
+	UserID    string    `gorm:"column:user_id;primaryKey" json:"userId"`
+	CreatedAt time.Time `gorm:"created_at" json:"createdAt"`
+}
+
+func (CardAssignee) TableName() string {
+	return "card_assignees"
+}
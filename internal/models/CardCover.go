@@ -0,0 +1,9 @@
+package models
+
+// CardCover is shown on top of a card: either a solid color or a thumbnail of one of its image attachments
+type CardCover struct {
+	Color        string  `gorm:"column:color" json:"color"`                // #RRGGBB, average color of the thumbnail for image covers
+	AttachmentID *string `gorm:"column:attachment_id" json:"attachmentId"` // set for image covers
+	Thumbnail    string  `gorm:"column:thumbnail" json:"thumbnail"`        // base64 JPEG data URL
+	TextColor    string  `gorm:"column:text_color" json:"textColor"`       // color of a text written on the cover
+}
@@ -0,0 +1,12 @@
+package models
+
+const (
+	CardDueOverdue  = "overdue" // due date passed and card not completed
+	CardDueThisWeek = "week"    // due date between monday and sunday of the current week
+)
+
+// CardFilter restricts the cards returned with a board, an empty filter returns all cards
+type CardFilter struct {
+	LabelIDs []string // cards having at least one of these labels
+	Due      string   // one of the CardDue constants, empty for no restriction
+}
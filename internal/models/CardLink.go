@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+const (
+	CardLinkBlocks     = "blocks"
+	CardLinkRelatesTo  = "relates"
+	CardLinkDuplicates = "duplicates"
+)
+
+// CardLink is a typed link from a card to another card, possibly on another board.
+// For a blocking link, CardID blocks LinkedCardID
+type CardLink struct {
+	ID           string    `gorm:"column:id;primaryKey" json:"id"`
+	CardID       string    `gorm:"column:card_id" json:"cardId"`
+	LinkedCardID string    `gorm:"column:linked_card_id" json:"linkedCardId"`
+	Type         string    `gorm:"column:type" json:"type"`
+	Card         *Card     `gorm:"foreignKey:CardID" json:"card,omitempty"`
+	LinkedCard   *Card     `gorm:"foreignKey:LinkedCardID" json:"linkedCard,omitempty"`
+	CreatedAt    time.Time `gorm:"created_at" json:"createdAt"`
+}
+
+func (CardLink) TableName() string {
+	return "card_links"
+}
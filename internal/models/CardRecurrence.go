@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// CardRecurrence makes the scheduler copy its card at each occurrence of its rule, with the checklist items unchecked
+type CardRecurrence struct {
+	CardID          string    `gorm:"column:card_id;primaryKey" json:"cardId"`
+	Rule            string    `gorm:"column:rule" json:"rule"`                         // daily, weekly, monthly or an RRULE with FREQ, INTERVAL, BYDAY and BYMONTHDAY
+	ListID          string    `gorm:"column:list_id" json:"listId"`                    // list of the copies, the list of the card by default
+	StartAt         time.Time `gorm:"column:start_at" json:"startAt"`                  // first occurrence whose time of day is kept, now by default
+	DueAfterSeconds int       `gorm:"column:due_after_seconds" json:"dueAfterSeconds"` // due date of a copy after its occurrence, the next occurrence when 0
+	NextAt          time.Time `gorm:"column:next_at" json:"nextAt"`                    // next occurrence, calculated
+	CreatedAt       time.Time `gorm:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (CardRecurrence) TableName() string {
+	return "card_recurrences"
+}
@@ -6,7 +6,8 @@ type ChecklistItem struct {
 	ID          string    `gorm:"column:id;primaryKey" json:"id"`
 	Title       string    `gorm:"column:title" json:"title"`
 	ChecklistID string    `gorm:"column:checklist_id" json:"checklistId"`
-	Position    int       `gorm:"column:position" json:"position"`
+	Rank        string    `gorm:"column:rank_key" json:"rank"`
+	Position    int       `gorm:"-" json:"position"` // from 1, calculated from the ranks when the item is loaded with its siblings
 	Checked     bool      `gorm:"column:checked" json:"checked"`
 	CreatedAt   time.Time `gorm:"created_at" json:"createdAt"`
 	UpdatedAt   time.Time `gorm:"updated_at" json:"updatedAt"`
@@ -3,14 +3,32 @@ package models
 import "time"
 
 type Comment struct {
+	ID        string            `gorm:"column:id;primaryKey" json:"id"`
+	CardID    string            `gorm:"column:card_id" json:"cardId"`
+	ParentID  *string           `gorm:"column:parent_id" json:"parentId"` // comment replied to, threads have one level
+	UserID    string            `gorm:"column:user_id" json:"userId"`     // author, only the author and board owners can edit or delete the comment
+	Content   string            `gorm:"column:content" json:"content"`
+	Replies   []Comment         `gorm:"foreignKey:ParentID" json:"replies"` // oldest first, loaded with top-level comments only
+	Reactions []ReactionSummary `gorm:"-" json:"reactions"`
+	CreatedAt time.Time         `gorm:"created_at" json:"createdAt"`
+	UpdatedAt time.Time         `gorm:"updated_at" json:"updatedAt"`
+	EditedAt  *time.Time        `gorm:"column:edited_at" json:"editedAt"`   // last change of the content, nil if never edited
+	DeletedAt *time.Time        `gorm:"column:deleted_at" json:"deletedAt"` // a deleted comment stays as an empty placeholder in its thread
+}
+
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// CommentRevision is a version of the content of a comment, the last revision is the current content
+type CommentRevision struct {
 	ID        string    `gorm:"column:id;primaryKey" json:"id"`
-	CardID    string    `gorm:"column:card_id" json:"cardId"`
-	UserID    string    `gorm:"column:user_id" json:"userId"`
+	CommentID string    `gorm:"column:comment_id" json:"commentId"`
+	UserID    string    `gorm:"column:user_id" json:"userId"` // who wrote this version
 	Content   string    `gorm:"column:content" json:"content"`
 	CreatedAt time.Time `gorm:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `gorm:"updated_at" json:"updatedAt"`
 }
 
-func (Comment) TableName() string {
-	return "comments"
+func (CommentRevision) TableName() string {
+	return "comment_revisions"
 }
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	CustomFieldTypeText     = "text"
+	CustomFieldTypeNumber   = "number"
+	CustomFieldTypeDate     = "date"
+	CustomFieldTypeCheckbox = "checkbox"
+	CustomFieldTypeDropdown = "dropdown"
+)
+
+type CustomField struct {
+	ID        string    `gorm:"column:id;primaryKey" json:"id"`
+	BoardID   string    `gorm:"column:board_id" json:"boardId"`
+	Name      string    `gorm:"column:name" json:"name"`
+	Type      string    `gorm:"column:type" json:"type"`
+	Options   []string  `gorm:"column:options;serializer:json" json:"options"` // choices of a dropdown field
+	Position  int       `gorm:"column:position" json:"position"`
+	CreatedAt time.Time `gorm:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (CustomField) TableName() string {
+	return "custom_fields"
+}
+
+// CustomFieldValue is the value of a custom field on a card, stored as text:
+// numbers as decimals, dates as YYYY-MM-DD, checkboxes as true/false and dropdowns as one of the options
+type CustomFieldValue struct {
+	CardID        string       `gorm:"column:card_id;primaryKey" json:"cardId"`
+	CustomFieldID string       `gorm:"column:custom_field_id;primaryKey" json:"customFieldId"`
+	CustomField   *CustomField `gorm:"foreignKey:CustomFieldID" json:"-"`
+	Value         string       `gorm:"column:value" json:"value"`
+	CreatedAt     time.Time    `gorm:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time    `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (CustomFieldValue) TableName() string {
+	return "custom_field_values"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type Label struct {
+	ID        string    `gorm:"column:id;primaryKey" json:"id"`
+	BoardID   string    `gorm:"column:board_id" json:"boardId"`
+	Name      string    `gorm:"column:name" json:"name"`
+	Color     string    `gorm:"column:color" json:"color"`
+	CreatedAt time.Time `gorm:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (Label) TableName() string {
+	return "labels"
+}
+
+type CardLabel struct {
+	CardID  string `gorm:"column:card_id;primaryKey" json:"cardId"`
+	LabelID string `gorm:"column:label_id;primaryKey" json:"labelId"`
+}
+
+func (CardLabel) TableName() string {
+	return "card_labels"
+}
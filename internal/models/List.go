@@ -3,14 +3,20 @@ package models
 import "time"
 
 type List struct {
-	ID         string     `gorm:"column:id;primaryKey" json:"id"`
-	BoardID    string     `gorm:"column:board_id" json:"boardId"`
-	Title      string     `gorm:"column:title" json:"title"`
-	Position   int        `gormjson:"position"`
-	Cards      []Card     ` gorm:"foreignKey:ListID" json:"cards"`
-	CreatedAt  time.Time  `gorm:"created_at" json:"createdAt"`
-	UpdatedAt  time.Time  `gorm:"updated_at" json:"updatedAt"`
-	ArchivedAt *time.Time `gorm:"archived_at" json:"archivedAt"`
+	ID            string     `gorm:"column:id;primaryKey" json:"id"`
+	BoardID       string     `gorm:"column:board_id" json:"boardId"`
+	Title         string     `gorm:"column:title" json:"title"`
+	Rank          string     `gorm:"column:rank_key" json:"rank"`
+	Position      int        `gorm:"-" json:"position"`                          // from 1, calculated from the ranks when the list is loaded with its siblings
+	IsDone        bool       `gorm:"column:is_done" json:"isDone"`               // cards in this list are considered done
+	RefuseBlocked bool       `gorm:"column:refuse_blocked" json:"refuseBlocked"` // when done, blocked cards cannot be moved into this list
+	WipLimit      int        `gorm:"column:wip_limit" json:"wipLimit"`           // maximum number of open cards, no limit when 0
+	WipHard       bool       `gorm:"column:wip_hard" json:"wipHard"`             // cards beyond the WIP limit are refused, only warned about otherwise
+	CardCount     int        `gorm:"-" json:"cardCount"`                         // number of open cards, calculated when the list is loaded with its cards
+	Cards         []Card     ` gorm:"foreignKey:ListID" json:"cards"`
+	CreatedAt     time.Time  `gorm:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"updated_at" json:"updatedAt"`
+	ArchivedAt    *time.Time `gorm:"archived_at" json:"archivedAt"`
 }
 
 func (balise *List) TableName() string {
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	NotificationTypeMention = "mention"
+)
+
+// Notification tells a user that something happened to them on a card, it is read by its user only
+type Notification struct {
+	ID        string     `gorm:"column:id;primaryKey" json:"id"`
+	UserID    string     `gorm:"column:user_id" json:"userId"` // notified user
+	Type      string     `gorm:"column:type" json:"type"`      // NotificationType...
+	ActorID   string     `gorm:"column:actor_id" json:"actorId"`
+	Actor     *User      `gorm:"foreignKey:ActorID" json:"actor"`
+	BoardID   string     `gorm:"column:board_id" json:"boardId"`
+	CardID    string     `gorm:"column:card_id" json:"cardId"`
+	Card      *Card      `gorm:"foreignKey:CardID" json:"card"`
+	CommentID string     `gorm:"column:comment_id" json:"commentId"` // empty for a mention in the description of the card
+	ReadAt    *time.Time `gorm:"column:read_at" json:"readAt"`
+	CreatedAt time.Time  `gorm:"created_at" json:"createdAt"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
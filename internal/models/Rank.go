@@ -0,0 +1,26 @@
+package models
+
+// NumberLists sets the positions of lists ordered by rank, and of their cards
+func NumberLists(lists []List) {
+	for i := range lists {
+		lists[i].Position = i + 1
+		NumberCards(lists[i].Cards)
+	}
+}
+
+// NumberCards sets the positions of cards ordered by rank, and of their checklist items
+func NumberCards(cards []Card) {
+	for i := range cards {
+		cards[i].Position = i + 1
+		for j := range cards[i].Checklists {
+			NumberChecklistItems(cards[i].Checklists[j].Items)
+		}
+	}
+}
+
+// NumberChecklistItems sets the positions of checklist items ordered by rank
+func NumberChecklistItems(items []ChecklistItem) {
+	for i := range items {
+		items[i].Position = i + 1
+	}
+}
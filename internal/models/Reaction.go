@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	ReactionTargetCard    = "card"
+	ReactionTargetComment = "comment"
+)
+
+// Reaction is an emoji put by a user on a card or a comment, a user puts each emoji at most once on a target
+type Reaction struct {
+	TargetType string    `gorm:"column:target_type;primaryKey" json:"targetType"` // ReactionTarget...
+	TargetID   string    `gorm:"column:target_id;primaryKey" json:"targetId"`
+	UserID     string    `gorm:"column:user_id;primaryKey" json:"userId"`
+	Emoji      string    `gorm:"column:emoji;primaryKey" json:"emoji"`
+	CreatedAt  time.Time `gorm:"created_at" json:"createdAt"`
+}
+
+func (Reaction) TableName() string {
+	return "reactions"
+}
+
+// ReactionSummary is how many users put an emoji on a card or a comment, and whether the current user is one of them
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reactedByMe"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// SentReminder records a reminder sent for a card, a new reminder is sent when the due date of the card changes
+type SentReminder struct {
+	CardID        string    `gorm:"column:card_id;primaryKey" json:"cardId"`
+	OffsetSeconds int       `gorm:"column:offset_seconds;primaryKey" json:"offsetSeconds"` // how long before the due date
+	DueAt         time.Time `gorm:"column:due_at;primaryKey" json:"dueAt"`
+	SentAt        time.Time `gorm:"column:sent_at" json:"sentAt"`
+}
+
+func (SentReminder) TableName() string {
+	return "sent_reminders"
+}
+
+// DueCard is an open card approaching its due date, with the board it belongs to
+type DueCard struct {
+	CardID     string    `gorm:"column:card_id" json:"cardId"`
+	Title      string    `gorm:"column:title" json:"title"`
+	DueAt      time.Time `gorm:"column:due_at" json:"dueAt"`
+	BoardID    string    `gorm:"column:board_id" json:"boardId"`
+	BoardTitle string    `gorm:"column:board_title" json:"boardTitle"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+const (
+	RuleTriggerCardCreated        = "cardcreated"        // a card is created, in any list or in triggerListId
+	RuleTriggerCardMoved          = "cardmoved"          // a card is moved into triggerListId
+	RuleTriggerChecklistCompleted = "checklistcompleted" // all the items of a checklist of a card are checked
+	RuleTriggerDuePassed          = "duepassed"          // the due date of an open card has passed
+
+	RuleActionMoveCard = "movecard"     // move the card at the end of the list actionValue
+	RuleActionAddLabel = "addlabel"     // add the label actionValue to the card
+	RuleActionAssign   = "assignmember" // assign the user actionValue to the card
+	RuleActionCheckAll = "checkall"     // check all the items of the checklists of the card
+	RuleActionArchive  = "archive"      // archive the card
+	RuleActionComment  = "comment"      // post the comment actionValue on the card
+)
+
+// AutomationUserID is the author of the logs and comments of the operations made by rules
+const AutomationUserID = "automation"
+
+// Rule is an automation of a board: its action is applied to the card of each event matching its trigger
+type Rule struct {
+	ID            string    `gorm:"column:id;primaryKey" json:"id"`
+	BoardID       string    `gorm:"column:board_id" json:"boardId"`
+	Name          string    `gorm:"column:name" json:"name"`
+	Trigger       string    `gorm:"column:trigger_type" json:"trigger"`
+	TriggerListID string    `gorm:"column:trigger_list_id" json:"triggerListId"` // list of the cardcreated (optional) and cardmoved triggers
+	Action        string    `gorm:"column:action_type" json:"action"`
+	ActionValue   string    `gorm:"column:action_value" json:"actionValue"` // list, label, user or comment text depending on the action
+	Enabled       bool      `gorm:"column:enabled" json:"enabled"`
+	CreatedAt     time.Time `gorm:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (Rule) TableName() string {
+	return "rules"
+}
+
+// DueRuleRun records that the rules triggered by the due date of a card have run,
+// they run again when the due date of the card changes
+type DueRuleRun struct {
+	CardID string    `gorm:"column:card_id;primaryKey" json:"cardId"`
+	DueAt  time.Time `gorm:"column:due_at;primaryKey" json:"dueAt"`
+	RunAt  time.Time `gorm:"column:run_at" json:"runAt"`
+}
+
+func (DueRuleRun) TableName() string {
+	return "due_rule_runs"
+}
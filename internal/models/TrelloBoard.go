@@ -0,0 +1,110 @@
+package models
+
+import "time"
+
+// TrelloBoard is the subset of a Trello board JSON export read by the importer
+type TrelloBoard struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Desc         string             `json:"desc"`
+	Closed       bool               `json:"closed"`
+	Prefs        TrelloPrefs        `json:"prefs"`
+	Labels       []TrelloLabel      `json:"labels"`
+	Lists        []TrelloList       `json:"lists"`
+	Cards        []TrelloCard       `json:"cards"`
+	Checklists   []TrelloChecklist  `json:"checklists"`
+	Actions      []TrelloAction     `json:"actions"`
+	Members      []TrelloMember     `json:"members"`
+	CustomFields []TrelloCustomItem `json:"customFields"`
+}
+
+type TrelloPrefs struct {
+	Background      string `json:"background"`
+	BackgroundColor string `json:"backgroundColor"`
+	BackgroundImage string `json:"backgroundImage"`
+}
+
+type TrelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TrelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type TrelloCard struct {
+	ID               string             `json:"id"`
+	IDList           string             `json:"idList"`
+	Name             string             `json:"name"`
+	Desc             string             `json:"desc"`
+	Closed           bool               `json:"closed"`
+	Pos              float64            `json:"pos"`
+	Start            *time.Time         `json:"start"`
+	Due              *time.Time         `json:"due"`
+	DueComplete      bool               `json:"dueComplete"`
+	DateLastActivity time.Time          `json:"dateLastActivity"`
+	IDLabels         []string           `json:"idLabels"`
+	IDMembers        []string           `json:"idMembers"`
+	Attachments      []TrelloCustomItem `json:"attachments"`
+	CustomFieldItems []TrelloCustomItem `json:"customFieldItems"`
+}
+
+type TrelloChecklist struct {
+	ID         string            `json:"id"`
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []TrelloCheckItem `json:"checkItems"`
+}
+
+type TrelloCheckItem struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	State string  `json:"state"` // complete or incomplete
+	Pos   float64 `json:"pos"`
+}
+
+type TrelloAction struct {
+	ID            string           `json:"id"`
+	Type          string           `json:"type"`
+	Date          time.Time        `json:"date"`
+	Data          TrelloActionData `json:"data"`
+	MemberCreator TrelloMember     `json:"memberCreator"`
+}
+
+type TrelloActionData struct {
+	Text string          `json:"text"`
+	Card TrelloReference `json:"card"`
+}
+
+type TrelloReference struct {
+	ID string `json:"id"`
+}
+
+type TrelloMember struct {
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
+	Username string `json:"username"`
+}
+
+// TrelloCustomItem is used for Trello features that are not imported, only counted
+type TrelloCustomItem struct {
+	ID string `json:"id"`
+}
+
+// TrelloImportReport tells what was imported from a Trello export and which Trello features could not be mapped
+type TrelloImportReport struct {
+	BoardID        string         `json:"boardId"`
+	Lists          int            `json:"lists"`
+	Cards          int            `json:"cards"`
+	Checklists     int            `json:"checklists"`
+	ChecklistItems int            `json:"checklistItems"`
+	Comments       int            `json:"comments"`
+	Labels         int            `json:"labels"`
+	Unmapped       map[string]int `json:"unmapped"` // Trello feature -> number of occurrences not imported
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)
+
+type Workspace struct {
+	ID          string             `gorm:"column:id;primaryKey" json:"id"`
+	UserID      string             `gorm:"column:user_id" json:"userId"`
+	Name        string             `gorm:"column:name" json:"name"`
+	Description string             `gorm:"column:description" json:"description"`
+	DefaultRole string             `gorm:"column:default_role" json:"defaultRole"` // board role given to workspace members on the workspace boards
+	Members     []*WorkspaceMember `gorm:"foreignKey:WorkspaceID" json:"members"`
+	CreatedAt   time.Time          `gorm:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time          `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+type WorkspaceMember struct {
+	WorkspaceID string    `gorm:"column:workspace_id;primaryKey" json:"workspaceId"`
+	UserID      string    `gorm:"column:user_id;primaryKey" json:"userId"`
+	User        *User     `gorm:"foreignKey:UserID" json:"user"`
+	Email       string    `gorm:"-" json:"email"` // used to invite a user by email
+	Role        string    `gorm:"column:role" json:"role"`
+	CreatedAt   time.Time `gorm:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"updated_at" json:"updatedAt"`
+}
+
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"trellode-go/internal/member/access"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/tools"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+type NotificationRepositoryInterface interface {
+	GetNotifications(models.Context, bool, int, int) ([]*models.Notification, int, error)
+	GetUnreadCount(models.Context) (int, int, error)
+	MarkRead(models.Context, string) (int, error)
+	MarkAllRead(models.Context) (int, error)
+}
+
+func NewNotificationRepository(db *gorm.DB, log *zap.Logger) NotificationRepository {
+	return NotificationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetNotifications returns the notifications of the current user, most recent first
+func (repo NotificationRepository) GetNotifications(context models.Context, unreadOnly bool, pageSize int, pageIndex int) ([]*models.Notification, int, error) {
+	pageSize, _, offset := tools.CheckPagination(pageSize, pageIndex)
+
+	notifications := []*models.Notification{}
+	query := repo.db.
+		Preload("Actor").
+		Preload("Card").
+		Where("user_id = ? AND board_id IN (?)", context.UserId, access.VisibleBoards(repo.db, context.UserId))
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.
+		Order("created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return notifications, http.StatusOK, nil
+}
+
+// GetUnreadCount returns how many notifications of the current user are unread
+func (repo NotificationRepository) GetUnreadCount(context models.Context) (int, int, error) {
+	var count int64
+	err := repo.db.
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL AND board_id IN (?)", context.UserId, access.VisibleBoards(repo.db, context.UserId)).
+		Count(&count).Error
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+
+	return int(count), http.StatusOK, nil
+}
+
+// MarkRead marks a notification of the current user as read
+func (repo NotificationRepository) MarkRead(context models.Context, id string) (int, error) {
+	var notification models.Notification
+	err := repo.db.Where("id = ? AND user_id = ?", id, context.UserId).First(&notification).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if notification.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "NotificationNotFound"))
+	}
+	if notification.ReadAt != nil {
+		return http.StatusAccepted, nil
+	}
+
+	err = repo.db.Model(&models.Notification{}).Where("id = ?", id).Update("read_at", time.Now()).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// MarkAllRead marks all the notifications of the current user as read
+func (repo NotificationRepository) MarkAllRead(context models.Context) (int, error) {
+	err := repo.db.
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", context.UserId).
+		Update("read_at", time.Now()).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"trellode-go/internal/models"
+)
+
+type NotificationServiceInterface interface {
+	GetNotifications(models.Context, bool, int, int) ([]*models.Notification, int, error)
+	GetUnreadCount(models.Context) (int, int, error)
+	MarkRead(models.Context, string) (int, error)
+	MarkAllRead(models.Context) (int, error)
+}
+
+type NotificationService struct {
+	repo NotificationRepositoryInterface
+}
+
+// NewNotificationService returns a service to read the notifications of the current user
+func NewNotificationService(repo NotificationRepositoryInterface) NotificationService {
+	return NotificationService{
+		repo: repo,
+	}
+}
+
+func (s NotificationService) GetNotifications(context models.Context, unreadOnly bool, pageSize int, pageIndex int) ([]*models.Notification, int, error) {
+	return s.repo.GetNotifications(context, unreadOnly, pageSize, pageIndex)
+}
+
+func (s NotificationService) GetUnreadCount(context models.Context) (int, int, error) {
+	return s.repo.GetUnreadCount(context)
+}
+
+func (s NotificationService) MarkRead(context models.Context, id string) (int, error) {
+	return s.repo.MarkRead(context, id)
+}
+
+func (s NotificationService) MarkAllRead(context models.Context) (int, error) {
+	return s.repo.MarkAllRead(context)
+}
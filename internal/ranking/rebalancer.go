@@ -0,0 +1,81 @@
+package ranking
+
+import (
+	"context"
+	"time"
+	"trellode-go/internal/utils/rank"
+	"trellode-go/internal/utils/schedule"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultInterval = time.Hour
+
+// Rebalancer periodically gives evenly spaced ranks to the siblings whose ranks became too long
+// after many moves to the same place
+type Rebalancer struct {
+	repo     RankingRepositoryInterface
+	interval time.Duration
+	log      *zap.Logger
+}
+
+func NewRebalancer(repo RankingRepositoryInterface, interval time.Duration, log *zap.Logger) Rebalancer {
+	return Rebalancer{
+		repo:     repo,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// NewRebalancerFromEnv returns a rebalancer running every RANK_REBALANCE_INTERVAL (a duration, "none" to disable it)
+func NewRebalancerFromEnv(db *gorm.DB, log *zap.Logger) (Rebalancer, error) {
+	interval, err := schedule.Interval("RANK_REBALANCE_INTERVAL", defaultInterval)
+	if err != nil {
+		return Rebalancer{}, err
+	}
+
+	return NewRebalancer(NewRankingRepository(db, log), interval, log), nil
+}
+
+// Run rebalances ranks every interval until ctx is done
+func (r Rebalancer) Run(ctx context.Context) {
+	schedule.Task{
+		Interval: r.interval,
+		Job: func(time.Time) (int, error) {
+			return r.Rebalance()
+		},
+		Disabled: "Rank rebalancing is disabled",
+		Failed:   "Failed to rebalance ranks",
+		Done:     "Rebalanced %d rank(s)",
+	}.Run(ctx, r.log)
+}
+
+// Rebalance rebalances the ranks of all unbalanced siblings and returns the number of rows updated,
+// it goes on with the other parents when one fails and returns the first error
+func (r Rebalancer) Rebalance() (int, error) {
+	updated := 0
+	var firstErr error
+	for _, table := range RankedTables {
+		parentIds, err := r.repo.GetUnbalancedParents(table, rank.MaxLength)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, parentId := range parentIds {
+			count, err := r.repo.Rebalance(table, parentId)
+			if err != nil {
+				r.log.Warn("Failed to rebalance " + table.Name + " of " + parentId + ": " + err.Error())
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			updated += count
+		}
+	}
+
+	return updated, firstErr
+}
@@ -0,0 +1,44 @@
+package ranking
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	unbalanced map[string][]string
+	failing    string
+	rebalanced []string
+}
+
+func (repo *fakeRepository) GetUnbalancedParents(table RankedTable, maxLength int) ([]string, error) {
+	return repo.unbalanced[table.Name], nil
+}
+
+func (repo *fakeRepository) Rebalance(table RankedTable, parentId string) (int, error) {
+	if parentId == repo.failing {
+		return 0, errors.New("rebalance failed")
+	}
+	repo.rebalanced = append(repo.rebalanced, table.Name+"/"+parentId)
+	return 10, nil
+}
+
+func TestRebalance(t *testing.T) {
+	repo := &fakeRepository{
+		unbalanced: map[string][]string{
+			"lists":          {"board1"},
+			"cards":          {"list1", "list2", "list3"},
+			"checklistitems": {},
+		},
+		failing: "list2",
+	}
+	rebalancer := NewRebalancer(repo, 0, zap.NewNop())
+
+	updated, err := rebalancer.Rebalance()
+	assert.EqualError(t, err, "rebalance failed")
+	assert.Equal(t, 30, updated)
+	assert.Equal(t, []string{"lists/board1", "cards/list1", "cards/list3"}, repo.rebalanced)
+}
@@ -0,0 +1,88 @@
+package ranking
+
+import (
+	"trellode-go/internal/utils/rank"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RankedTable is a table whose rows are ordered by rank among the rows sharing the same parent
+type RankedTable struct {
+	Name         string
+	ParentColumn string
+}
+
+// RankedTables are the tables ordered by rank: lists of a board, cards of a list, items of a checklist
+var RankedTables = []RankedTable{
+	{Name: "lists", ParentColumn: "board_id"},
+	{Name: "cards", ParentColumn: "list_id"},
+	{Name: "checklistitems", ParentColumn: "checklist_id"},
+}
+
+type RankingRepository struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+type RankingRepositoryInterface interface {
+	GetUnbalancedParents(RankedTable, int) ([]string, error)
+	Rebalance(RankedTable, string) (int, error)
+}
+
+func NewRankingRepository(db *gorm.DB, log *zap.Logger) RankingRepository {
+	return RankingRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetUnbalancedParents returns the parents whose children have ranks longer than maxLength, missing or duplicate ranks
+func (repo RankingRepository) GetUnbalancedParents(table RankedTable, maxLength int) ([]string, error) {
+	parentIds := []string{}
+	err := repo.db.
+		Table(table.Name).
+		Select(table.ParentColumn).
+		Group(table.ParentColumn).
+		Having("MAX(LENGTH(rank_key)) > ? OR MIN(rank_key) = '' OR COUNT(DISTINCT rank_key) < COUNT(*)", maxLength).
+		Pluck(table.ParentColumn, &parentIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return parentIds, nil
+}
+
+// Rebalance gives evenly spaced ranks to the children of a parent, keeping their order, and returns the number of rows updated
+func (repo RankingRepository) Rebalance(table RankedTable, parentId string) (int, error) {
+	tx := repo.db.Begin()
+
+	ids := []string{}
+	err := tx.
+		Table(table.Name).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(table.ParentColumn+" = ?", parentId).
+		Order("rank_key ASC, created_at ASC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	ranks := rank.Sequence(len(ids))
+	for i, id := range ids {
+		err = tx.Table(table.Name).Where("id = ?", id).Update("rank_key", ranks[i]).Error
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
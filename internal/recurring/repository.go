@@ -0,0 +1,241 @@
+package recurring
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"trellode-go/internal/automation"
+	"trellode-go/internal/log"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/clone"
+	"trellode-go/internal/utils/rank"
+	"trellode-go/internal/utils/recurrence"
+	"trellode-go/internal/utils/wip"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringRepository struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	logService log.LogService
+	automation automation.Engine
+}
+
+type RecurringRepositoryInterface interface {
+	GetDueRecurrences(time.Time) ([]*models.CardRecurrence, error)
+	CreateOccurrence(*models.CardRecurrence, time.Time) (string, error)
+}
+
+func NewRecurringRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) RecurringRepository {
+	return RecurringRepository{
+		db:         db,
+		log:        log,
+		logService: logService,
+		automation: automation.NewEngine(logService, log),
+	}
+}
+
+// GetDueRecurrences returns the recurrences of open cards, on open lists and boards, whose next occurrence is before now
+func (repo RecurringRepository) GetDueRecurrences(now time.Time) ([]*models.CardRecurrence, error) {
+	recurrences := []*models.CardRecurrence{}
+	err := repo.db.
+		Joins("JOIN cards ON cards.id = card_recurrences.card_id").
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Joins("JOIN boards ON boards.id = lists.board_id").
+		Where("cards.archived_at IS NULL AND lists.archived_at IS NULL AND boards.archived_at IS NULL").
+		Where("card_recurrences.next_at <= ?", now).
+		Order("card_recurrences.next_at ASC").
+		Find(&recurrences).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return recurrences, nil
+}
+
+// CreateOccurrence copies the card of the recurrence at the end of its list, with the checklist items unchecked,
+// and moves the recurrence to its first occurrence after now: occurrences missed while the scheduler was stopped
+// make a single copy, and none is made beyond the hard WIP limit of the list. Returns the ID of the copy, empty
+// when no copy was made.
+func (repo RecurringRepository) CreateOccurrence(due *models.CardRecurrence, now time.Time) (string, error) {
+	tx := repo.db.Begin()
+
+	// another instance may have made the copy in the meantime
+	var cardRecurrence models.CardRecurrence
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("card_id = ? AND next_at <= ?", due.CardID, now).
+		First(&cardRecurrence).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return "", nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	occurrence, next, err := occurrences(&cardRecurrence, now)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	// no more occurrences, the recurrence ends
+	if next.IsZero() {
+		err = tx.Where("card_id = ?", cardRecurrence.CardID).Delete(&models.CardRecurrence{}).Error
+	} else {
+		err = tx.Model(&models.CardRecurrence{}).
+			Where("card_id = ?", cardRecurrence.CardID).
+			Updates(map[string]interface{}{"next_at": next, "updated_at": now}).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	var list models.List
+	err = tx.Where("id = ? AND archived_at IS NULL", cardRecurrence.ListID).First(&list).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return "", err
+	}
+	if list.ID == "" {
+		repo.log.Warn("Recurrence of card " + cardRecurrence.CardID + " skipped: list " + cardRecurrence.ListID + " not found")
+		return "", tx.Commit().Error
+	}
+	// a hard WIP limit skips the occurrence, a soft one is only logged with the copy
+	context := models.Context{UserId: models.AutomationUserID}
+	warning, severity, err := wip.Check(context, tx, &list, "")
+	if severity == http.StatusConflict {
+		repo.log.Warn("Recurrence of card " + cardRecurrence.CardID + " skipped: " + err.Error())
+		return "", tx.Commit().Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	var source models.Card
+	err = tx.
+		Preload("Checklists", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL")
+		}).
+		Preload("Checklists.Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Labels").
+		Preload("CustomFieldValues").
+		Where("id = ?", cardRecurrence.CardID).
+		First(&source).Error
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	prepareCopy(&source, &cardRecurrence, occurrence, next)
+
+	// labels and custom fields are kept on the same board, matched by name on another board
+	var sourceList models.List
+	err = tx.Where("id = ?", source.ListID).First(&sourceList).Error
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	mapping, err := clone.Match(tx, sourceList.BoardID, list.BoardID)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	// the copy goes at the end of the list
+	var lastRank *string
+	err = tx.Model(&models.Card{}).Where("list_id = ?", list.ID).Select("MAX(rank_key)").Row().Scan(&lastRank)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	last := ""
+	if lastRank != nil {
+		last = *lastRank
+	}
+	card, err := clone.Card(tx, &source, list.ID, rank.Between(last, ""), false, mapping)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	// log operation
+	changes := []*models.LogChange{{
+		Field:     "copiedfrom",
+		FromValue: source.ID,
+		ToValue:   card.ID,
+	}}
+	if warning != "" {
+		changes = append(changes, &models.LogChange{
+			Field:   "wipLimit",
+			ToValue: warning,
+		})
+	}
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	_, _, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        list.BoardID,
+		Action:         "recurcard",
+		ActionTargetID: card.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	err = repo.automation.Run(context, tx, automation.Event{
+		Trigger: models.RuleTriggerCardCreated,
+		BoardID: list.BoardID,
+		CardID:  card.ID,
+		ListID:  list.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	return card.ID, tx.Commit().Error
+}
+
+// occurrences returns the occurrence a copy is made for, the oldest one not copied yet, and the first occurrence
+// after now, where the recurrence goes next (zero when there is none): missed occurrences are skipped
+func occurrences(cardRecurrence *models.CardRecurrence, now time.Time) (time.Time, time.Time, error) {
+	rule, err := recurrence.Parse(cardRecurrence.Rule)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return cardRecurrence.NextAt, rule.Next(cardRecurrence.StartAt, now), nil
+}
+
+// prepareCopy turns the source card into its copy for occurrence: it starts at its occurrence, is due
+// DueAfterSeconds after it or else at the next occurrence, and has all its checklist items to do
+func prepareCopy(source *models.Card, cardRecurrence *models.CardRecurrence, occurrence time.Time, next time.Time) {
+	source.StartAt = &occurrence
+	source.DueAt = nil
+	if cardRecurrence.DueAfterSeconds > 0 {
+		dueAt := occurrence.Add(time.Duration(cardRecurrence.DueAfterSeconds) * time.Second)
+		source.DueAt = &dueAt
+	} else if !next.IsZero() {
+		source.DueAt = &next
+	}
+	source.CompletedAt = nil
+	for i := range source.Checklists {
+		for j := range source.Checklists[i].Items {
+			source.Checklists[i].Items[j].Checked = false
+		}
+	}
+}
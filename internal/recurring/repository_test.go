@@ -0,0 +1,72 @@
+package recurring
+
+import (
+	"testing"
+	"time"
+	"trellode-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOccurrencesCollapseMissed(t *testing.T) {
+	// daily at 8:00, the server was stopped from the 2nd to the 6th
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	cardRecurrence := &models.CardRecurrence{Rule: "daily", StartAt: start, NextAt: start.AddDate(0, 0, 1)}
+	now := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+
+	occurrence, next, err := occurrences(cardRecurrence, now)
+	assert.NoError(t, err)
+	// a single copy for the oldest missed occurrence, the next one is after now
+	assert.Equal(t, start.AddDate(0, 0, 1), occurrence)
+	assert.Equal(t, start.AddDate(0, 0, 6), next)
+
+	cardRecurrence.NextAt = next
+	_, next, err = occurrences(cardRecurrence, next)
+	assert.NoError(t, err)
+	assert.Equal(t, start.AddDate(0, 0, 7), next)
+}
+
+func TestOccurrencesInvalidRule(t *testing.T) {
+	_, _, err := occurrences(&models.CardRecurrence{Rule: "hourly"}, time.Now())
+	assert.Error(t, err)
+}
+
+func testSource() *models.Card {
+	completedAt := time.Date(2024, 4, 30, 17, 0, 0, 0, time.UTC)
+	return &models.Card{
+		Title:       "Weekly report",
+		CompletedAt: &completedAt,
+		DueAt:       &completedAt,
+		Checklists: []models.Checklist{
+			{Items: []models.ChecklistItem{{Title: "write", Checked: true}, {Title: "send", Checked: true}}},
+			{Items: []models.ChecklistItem{{Title: "archive"}}},
+		},
+	}
+}
+
+func TestPrepareCopy(t *testing.T) {
+	occurrence := time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)
+	next := occurrence.AddDate(0, 0, 7)
+
+	// due DueAfterSeconds after the occurrence
+	source := testSource()
+	prepareCopy(source, &models.CardRecurrence{DueAfterSeconds: 3600}, occurrence, next)
+	assert.Equal(t, occurrence, *source.StartAt)
+	assert.Equal(t, occurrence.Add(time.Hour), *source.DueAt)
+	assert.Nil(t, source.CompletedAt)
+	for _, checklist := range source.Checklists {
+		for _, item := range checklist.Items {
+			assert.False(t, item.Checked, item.Title)
+		}
+	}
+
+	// due at the next occurrence
+	source = testSource()
+	prepareCopy(source, &models.CardRecurrence{}, occurrence, next)
+	assert.Equal(t, next, *source.DueAt)
+
+	// no due date after the last occurrence
+	source = testSource()
+	prepareCopy(source, &models.CardRecurrence{}, occurrence, time.Time{})
+	assert.Nil(t, source.DueAt)
+}
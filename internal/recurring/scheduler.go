@@ -0,0 +1,73 @@
+package recurring
+
+import (
+	"context"
+	"time"
+	"trellode-go/internal/log"
+	"trellode-go/internal/utils/schedule"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultInterval = time.Minute
+
+// Scheduler periodically copies the recurring cards whose next occurrence has come
+type Scheduler struct {
+	repo     RecurringRepositoryInterface
+	interval time.Duration
+	log      *zap.Logger
+}
+
+func NewScheduler(repo RecurringRepositoryInterface, interval time.Duration, log *zap.Logger) Scheduler {
+	return Scheduler{
+		repo:     repo,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// NewSchedulerFromEnv returns a scheduler running every RECURRENCE_INTERVAL (a duration, "none" to disable recurring cards)
+func NewSchedulerFromEnv(db *gorm.DB, logger *zap.Logger) (Scheduler, error) {
+	interval, err := schedule.Interval("RECURRENCE_INTERVAL", defaultInterval)
+	if err != nil {
+		return Scheduler{}, err
+	}
+
+	logService := log.NewLogService(log.NewLogRepository(db, logger))
+	return NewScheduler(NewRecurringRepository(db, logger, logService), interval, logger), nil
+}
+
+// Run copies the recurring cards every interval until ctx is done
+func (s Scheduler) Run(ctx context.Context) {
+	schedule.Task{
+		Interval: s.interval,
+		Job:      s.CreateOccurrences,
+		Disabled: "Recurring cards are disabled",
+		Failed:   "Failed to create recurring cards",
+		Done:     "Created %d recurring card(s)",
+	}.Run(ctx, s.log)
+}
+
+// CreateOccurrences copies the recurring cards whose next occurrence is before now and returns how many copies were made
+func (s Scheduler) CreateOccurrences(now time.Time) (int, error) {
+	recurrences, err := s.repo.GetDueRecurrences(now)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, recurrence := range recurrences {
+		id, err := s.repo.CreateOccurrence(recurrence, now)
+		if err != nil {
+			// try the other cards, this one is retried on next run
+			s.log.Error("Failed to create recurring card from " + recurrence.CardID + ": " + err.Error())
+			continue
+		}
+		if id != "" {
+			created++
+		}
+	}
+
+	return created, nil
+}
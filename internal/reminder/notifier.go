@@ -0,0 +1,77 @@
+package reminder
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notifier delivers a notification to a list of email addresses
+type Notifier interface {
+	Notify(to []string, subject string, body string) error
+}
+
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPNotifier returns a notifier sending emails through the SMTP server host:port,
+// authentication is only used when username is set
+func NewSMTPNotifier(host string, port string, username string, password string, from string) SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return SMTPNotifier{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+		auth: auth,
+	}
+}
+
+func (n SMTPNotifier) Notify(to []string, subject string, body string) error {
+	if len(to) == 0 {
+		return nil
+	}
+	return smtp.SendMail(n.addr, n.auth, n.from, to, buildMessage(n.from, to, subject, body, time.Now()))
+}
+
+// buildMessage formats a plain text UTF-8 email
+func buildMessage(from string, to []string, subject string, body string, date time.Time) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	return msg.Bytes()
+}
+
+// LogNotifier only logs notifications, it is used when no SMTP server is configured
+type LogNotifier struct {
+	log *zap.Logger
+}
+
+func NewLogNotifier(log *zap.Logger) LogNotifier {
+	return LogNotifier{
+		log: log,
+	}
+}
+
+func (n LogNotifier) Notify(to []string, subject string, body string) error {
+	n.log.Info("Notification: "+subject, zap.Strings("to", to))
+	return nil
+}
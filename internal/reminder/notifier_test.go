@@ -0,0 +1,93 @@
+package reminder
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSMTPServer accepts one SMTP session on a local port and sends the received envelope and data on the returned channel
+func fakeSMTPServer(t *testing.T) (string, string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var session strings.Builder
+		reader := bufio.NewReader(conn)
+		reply := func(line string) {
+			conn.Write([]byte(line + "\r\n"))
+		}
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					reply("250 OK")
+					continue
+				}
+				session.WriteString(line)
+				continue
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM"), strings.HasPrefix(command, "RCPT TO"):
+				session.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case command == "DATA":
+				inData = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case command == "QUIT":
+				reply("221 Bye")
+				received <- session.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	notifier := NewSMTPNotifier(host, port, "", "", "trellode@localhost")
+
+	err := notifier.Notify([]string{"alice@example.com", "bob@example.com"}, "Reminder: écrire le rapport", "Due soon.\nHurry up.")
+	assert.Nil(t, err)
+
+	session := <-received
+	assert.Contains(t, session, "MAIL FROM:<trellode@localhost>")
+	assert.Contains(t, session, "RCPT TO:<alice@example.com>")
+	assert.Contains(t, session, "RCPT TO:<bob@example.com>")
+	assert.Contains(t, session, "To: alice@example.com, bob@example.com\r\n")
+	assert.Contains(t, session, "Subject: =?utf-8?q?Reminder:_=C3=A9crire_le_rapport?=\r\n")
+	assert.Contains(t, session, "\r\n\r\nDue soon.\r\nHurry up.\r\n")
+}
+
+func TestSMTPNotifierWithoutRecipient(t *testing.T) {
+	// nothing listens on this port, no connection must be attempted
+	notifier := NewSMTPNotifier("127.0.0.1", "1", "", "", "trellode@localhost")
+	assert.Nil(t, notifier.Notify([]string{}, "subject", "body"))
+}
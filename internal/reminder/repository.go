@@ -0,0 +1,106 @@
+package reminder
+
+import (
+	"time"
+	"trellode-go/internal/member/access"
+	"trellode-go/internal/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ReminderRepository struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+type ReminderRepositoryInterface interface {
+	GetDueCards(time.Time, time.Time) ([]*models.DueCard, error)
+	GetRecipients(*models.DueCard) ([]*models.User, error)
+	IsSent(*models.SentReminder) (bool, error)
+	SaveSent(*models.SentReminder, func() error) error
+}
+
+func NewReminderRepository(db *gorm.DB, log *zap.Logger) ReminderRepository {
+	return ReminderRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetDueCards returns the open cards, on open lists and boards, due after from and until to
+func (repo ReminderRepository) GetDueCards(from time.Time, to time.Time) ([]*models.DueCard, error) {
+	cards := []*models.DueCard{}
+	err := repo.db.
+		Table("cards").
+		Select("cards.id AS card_id, cards.title, cards.due_at, boards.id AS board_id, boards.title AS board_title").
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Joins("JOIN boards ON boards.id = lists.board_id").
+		Where("cards.archived_at IS NULL AND lists.archived_at IS NULL AND boards.archived_at IS NULL").
+		Where("cards.completed_at IS NULL AND cards.due_at > ? AND cards.due_at <= ?", from, to).
+		Order("cards.due_at ASC").
+		Scan(&cards).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// GetRecipients returns the assignees of a card who still have a role on its board,
+// or the owner and the members of its board when there are none
+func (repo ReminderRepository) GetRecipients(card *models.DueCard) ([]*models.User, error) {
+	users := []*models.User{}
+	err := repo.db.
+		Where("id IN (SELECT user_id FROM card_assignees WHERE card_id = ?) AND id IN (?)", card.CardID, access.BoardUsers(repo.db, card.BoardID)).
+		Order("email ASC").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(users) > 0 {
+		return users, nil
+	}
+
+	err = repo.db.
+		Where("id IN (SELECT user_id FROM boards WHERE id = ?) OR id IN (SELECT user_id FROM board_members WHERE board_id = ?)", card.BoardID, card.BoardID).
+		Order("email ASC").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (repo ReminderRepository) IsSent(reminder *models.SentReminder) (bool, error) {
+	var count int64
+	err := repo.db.
+		Model(&models.SentReminder{}).
+		Where("card_id = ? AND offset_seconds = ? AND due_at = ?", reminder.CardID, reminder.OffsetSeconds, reminder.DueAt).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// SaveSent records a reminder and calls send, the record is rolled back if sending fails so that it is retried later
+func (repo ReminderRepository) SaveSent(reminder *models.SentReminder, send func() error) error {
+	tx := repo.db.Begin()
+
+	err := tx.Create(reminder).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = send()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
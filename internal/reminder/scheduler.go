@@ -0,0 +1,191 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/schedule"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultOffsets  = "24h,1h"
+	defaultInterval = time.Minute
+)
+
+// Scheduler periodically sends reminders for the cards approaching their due date
+type Scheduler struct {
+	repo     ReminderRepositoryInterface
+	notifier Notifier
+	offsets  []time.Duration // how long before the due date reminders are sent, sorted in ascending order
+	interval time.Duration
+	log      *zap.Logger
+}
+
+func NewScheduler(repo ReminderRepositoryInterface, notifier Notifier, offsets []time.Duration, interval time.Duration, log *zap.Logger) Scheduler {
+	sorted := append([]time.Duration{}, offsets...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return Scheduler{
+		repo:     repo,
+		notifier: notifier,
+		offsets:  sorted,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// NewSchedulerFromEnv returns a scheduler configured with the environment variables:
+// REMINDER_OFFSETS (comma separated durations, "none" to disable reminders), REMINDER_INTERVAL
+// and SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM. Reminders are only logged without SMTP_HOST.
+func NewSchedulerFromEnv(db *gorm.DB, log *zap.Logger) (Scheduler, error) {
+	offsetsEnv := os.Getenv("REMINDER_OFFSETS")
+	if offsetsEnv == "" {
+		offsetsEnv = defaultOffsets
+	}
+	offsets, err := ParseOffsets(offsetsEnv)
+	if err != nil {
+		return Scheduler{}, err
+	}
+
+	interval := defaultInterval
+	if os.Getenv("REMINDER_INTERVAL") != "" {
+		interval, err = time.ParseDuration(os.Getenv("REMINDER_INTERVAL"))
+		if err != nil {
+			return Scheduler{}, err
+		}
+		if interval <= 0 {
+			return Scheduler{}, errors.New("REMINDER_INTERVAL must be positive")
+		}
+	}
+
+	var notifier Notifier = NewLogNotifier(log)
+	if os.Getenv("SMTP_HOST") != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "25"
+		}
+		notifier = NewSMTPNotifier(os.Getenv("SMTP_HOST"), port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"))
+	}
+
+	return NewScheduler(NewReminderRepository(db, log), notifier, offsets, interval, log), nil
+}
+
+// ParseOffsets parses a comma separated list of positive durations such as "24h,1h", "none" returns no offset
+func ParseOffsets(s string) ([]time.Duration, error) {
+	offsets := []time.Duration{}
+	if s == "none" {
+		return offsets, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		offset, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if offset <= 0 {
+			return nil, fmt.Errorf("reminder offset %s must be positive", offset)
+		}
+		offsets = append(offsets, offset)
+	}
+
+	return offsets, nil
+}
+
+// Run sends reminders every interval until ctx is done
+func (s Scheduler) Run(ctx context.Context) {
+	interval := s.interval
+	if len(s.offsets) == 0 {
+		interval = 0
+	}
+	schedule.Task{
+		Interval: interval,
+		Job:      s.SendReminders,
+		Disabled: "Reminders are disabled",
+		Failed:   "Failed to send reminders",
+		Done:     "Sent %d reminder(s)",
+	}.Run(ctx, s.log)
+}
+
+// SendReminders sends the reminders due at now and returns how many were sent.
+// A card only gets the reminder of the smallest offset reached, so that a card created
+// shortly before its due date does not get all the reminders at once.
+func (s Scheduler) SendReminders(now time.Time) (int, error) {
+	if len(s.offsets) == 0 {
+		return 0, nil
+	}
+
+	cards, err := s.repo.GetDueCards(now, now.Add(s.offsets[len(s.offsets)-1]))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, card := range cards {
+		offset, ok := dueOffset(s.offsets, card.DueAt, now)
+		if !ok {
+			continue
+		}
+		reminder := &models.SentReminder{
+			CardID:        card.CardID,
+			OffsetSeconds: int(offset.Seconds()),
+			DueAt:         card.DueAt,
+			SentAt:        now,
+		}
+		isSent, err := s.repo.IsSent(reminder)
+		if err != nil {
+			return sent, err
+		}
+		if isSent {
+			continue
+		}
+
+		users, err := s.repo.GetRecipients(card)
+		if err != nil {
+			return sent, err
+		}
+		to := []string{}
+		for _, user := range users {
+			if user.Email != "" {
+				to = append(to, user.Email)
+			}
+		}
+
+		subject, body := reminderMessage(card)
+		err = s.repo.SaveSent(reminder, func() error {
+			return s.notifier.Notify(to, subject, body)
+		})
+		if err != nil {
+			// try the other cards, this one is retried on next run
+			s.log.Error("Failed to send reminder of card " + card.CardID + ": " + err.Error())
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// dueOffset returns the smallest offset whose reminder time has been reached for a card due at dueAt
+func dueOffset(offsets []time.Duration, dueAt time.Time, now time.Time) (time.Duration, bool) {
+	for _, offset := range offsets {
+		if !now.Before(dueAt.Add(-offset)) {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+func reminderMessage(card *models.DueCard) (string, string) {
+	due := card.DueAt.Format("2006-01-02 15:04")
+	subject := fmt.Sprintf("Reminder: %s is due on %s", card.Title, due)
+	body := fmt.Sprintf("The card \"%s\" of the board \"%s\" is due on %s.", card.Title, card.BoardTitle, due)
+	return subject, body
+}
@@ -0,0 +1,99 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+	"trellode-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	cards []*models.DueCard
+	sent  []models.SentReminder
+}
+
+func (repo *fakeRepository) GetDueCards(from time.Time, to time.Time) ([]*models.DueCard, error) {
+	cards := []*models.DueCard{}
+	for _, card := range repo.cards {
+		if card.DueAt.After(from) && !card.DueAt.After(to) {
+			cards = append(cards, card)
+		}
+	}
+	return cards, nil
+}
+
+func (repo *fakeRepository) GetRecipients(card *models.DueCard) ([]*models.User, error) {
+	return []*models.User{{Email: "owner@example.com"}}, nil
+}
+
+func (repo *fakeRepository) IsSent(reminder *models.SentReminder) (bool, error) {
+	for _, sent := range repo.sent {
+		if sent.CardID == reminder.CardID && sent.OffsetSeconds == reminder.OffsetSeconds && sent.DueAt.Equal(reminder.DueAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (repo *fakeRepository) SaveSent(reminder *models.SentReminder, send func() error) error {
+	err := send()
+	if err != nil {
+		return err
+	}
+	repo.sent = append(repo.sent, *reminder)
+	return nil
+}
+
+type fakeNotifier struct {
+	subjects []string
+}
+
+func (n *fakeNotifier) Notify(to []string, subject string, body string) error {
+	n.subjects = append(n.subjects, subject)
+	return nil
+}
+
+func TestSendReminders(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{cards: []*models.DueCard{
+		{CardID: "1", Title: "tomorrow", DueAt: now.Add(20 * time.Hour)},
+		{CardID: "2", Title: "soon", DueAt: now.Add(30 * time.Minute)},
+		{CardID: "3", Title: "later", DueAt: now.Add(48 * time.Hour)},
+	}}
+	notifier := &fakeNotifier{}
+	scheduler := NewScheduler(repo, notifier, []time.Duration{time.Hour, 24 * time.Hour}, time.Minute, zap.NewNop())
+
+	sent, err := scheduler.SendReminders(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, sent)
+	// the card due soon only gets the 1 hour reminder
+	assert.Equal(t, 3600, repo.sent[1].OffsetSeconds)
+
+	// nothing is sent twice
+	sent, err = scheduler.SendReminders(now.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+
+	// the first card gets its 1 hour reminder
+	sent, err = scheduler.SendReminders(now.Add(19 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, "Reminder: tomorrow is due on 2024-05-02 06:00", notifier.subjects[2])
+}
+
+func TestParseOffsets(t *testing.T) {
+	offsets, err := ParseOffsets("24h, 1h30m")
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{24 * time.Hour, 90 * time.Minute}, offsets)
+
+	offsets, err = ParseOffsets("none")
+	assert.Nil(t, err)
+	assert.Empty(t, offsets)
+
+	_, err = ParseOffsets("-1h")
+	assert.NotNil(t, err)
+	_, err = ParseOffsets("tomorrow")
+	assert.NotNil(t, err)
+}
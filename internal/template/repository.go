@@ -0,0 +1,200 @@
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"trellode-go/internal/log"
+	"trellode-go/internal/member/access"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/clone"
+	"trellode-go/internal/utils/messages"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TemplateRepository struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	logService log.LogService
+}
+
+type TemplateRepositoryInterface interface {
+	GetTemplates(models.Context) ([]*models.Board, int, error)
+	SetTemplate(models.Context, string, bool) (int, error)
+	CreateBoardFromTemplate(models.Context, string, string) (string, int, error)
+}
+
+func NewTemplateRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) TemplateRepository {
+	return TemplateRepository{
+		db:         db,
+		log:        log,
+		logService: logService,
+	}
+}
+
+// GetTemplates returns the templates the current user has access to
+func (repo TemplateRepository) GetTemplates(context models.Context) ([]*models.Board, int, error) {
+	templates := []*models.Board{}
+	err := repo.db.
+		Preload("Background").
+		Where("id IN (?) AND is_template = 1 AND archived_at IS NULL", access.VisibleBoards(repo.db, context.UserId)).
+		Order("title ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return templates, http.StatusOK, nil
+}
+
+// SetTemplate marks or unmarks a board as template
+func (repo TemplateRepository) SetTemplate(context models.Context, boardId string, isTemplate bool) (int, error) {
+	var board models.Board
+	err := repo.db.Where("id = ?", boardId).First(&board).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if board.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	}
+
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "istemplate",
+		FromValue: strconv.FormatBool(board.IsTemplate),
+		ToValue:   strconv.FormatBool(isTemplate),
+	}})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.Board{}).Where("id = ?", boardId).Update("is_template", isTemplate).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	operation := "templateboard"
+	if !isTemplate {
+		operation = "untemplateboard"
+	}
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        boardId,
+		Action:         operation,
+		ActionTargetID: boardId,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// CreateBoardFromTemplate deep-copies a template (labels, custom fields, lists, cards, checklists with items, background) into a new board owned by the current user
+func (repo TemplateRepository) CreateBoardFromTemplate(context models.Context, templateId string, title string) (string, int, error) {
+	var template models.Board
+	err := repo.db.
+		Preload("Lists", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards.Checklists", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived_at IS NULL").Order("created_at DESC")
+		}).
+		Preload("Lists.Cards.Checklists.Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank_key ASC")
+		}).
+		Preload("Lists.Cards.Labels").
+		Preload("Lists.Cards.CustomFieldValues").
+		Preload("Labels").
+		Preload("CustomFields").
+		Where("id = ?", templateId).
+		First(&template).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if template.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "BoardNotFound"))
+	}
+	if !template.IsTemplate {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "BoardNotTemplate"))
+	}
+
+	if title == "" {
+		title = template.Title
+	}
+	board := models.Board{
+		ID:           uuid.NewString(),
+		UserID:       context.UserId,
+		Title:        title,
+		BackgroundID: template.BackgroundID,
+	}
+
+	tx := repo.db.Begin()
+
+	omitted := []string{"Background", "Lists", "Labels", "CustomFields"}
+	if board.BackgroundID == "" {
+		omitted = append(omitted, "BackgroundID")
+	}
+	err = tx.Omit(omitted...).Create(&board).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// creator is owner of the board
+	err = tx.Create(&models.BoardMember{
+		BoardID: board.ID,
+		UserID:  context.UserId,
+		Role:    models.BoardRoleOwner,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	err = clone.BoardContent(tx, &template, board.ID, false)
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	changesJson, err := json.Marshal([]*models.LogChange{{
+		Field:     "template",
+		FromValue: "",
+		ToValue:   template.ID,
+	}})
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        board.ID,
+		Action:         "createboard",
+		ActionTargetID: board.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return board.ID, http.StatusCreated, nil
+}
@@ -0,0 +1,47 @@
+package template
+
+import (
+	"trellode-go/internal/member"
+	"trellode-go/internal/models"
+)
+
+type TemplateServiceInterface interface {
+	GetTemplates(models.Context) ([]*models.Board, int, error)
+	SetTemplate(models.Context, string, bool) (int, error)
+	CreateBoardFromTemplate(models.Context, string, string) (string, int, error)
+}
+
+type TemplateService struct {
+	repo          TemplateRepositoryInterface
+	memberService member.MemberService
+}
+
+// NewTemplateService returns a service to manipulate board templates
+func NewTemplateService(repo TemplateRepositoryInterface, memberService member.MemberService) TemplateService {
+	return TemplateService{
+		repo:          repo,
+		memberService: memberService,
+	}
+}
+
+func (s TemplateService) GetTemplates(context models.Context) ([]*models.Board, int, error) {
+	return s.repo.GetTemplates(context)
+}
+
+func (s TemplateService) SetTemplate(context models.Context, boardId string, isTemplate bool) (int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, boardId, models.BoardRoleOwner)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.SetTemplate(context, boardId, isTemplate)
+}
+
+func (s TemplateService) CreateBoardFromTemplate(context models.Context, templateId string, title string) (string, int, error) {
+	severity, err := s.memberService.CheckBoardRole(context, templateId, models.BoardRoleViewer)
+	if err != nil {
+		return "", severity, err
+	}
+
+	return s.repo.CreateBoardFromTemplate(context, templateId, title)
+}
@@ -0,0 +1,331 @@
+package trello
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"trellode-go/internal/log"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+	"trellode-go/internal/utils/rank"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TrelloRepository struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	logService log.LogService
+}
+
+type TrelloRepositoryInterface interface {
+	ImportBoard(models.Context, *models.TrelloBoard) (*models.TrelloImportReport, int, error)
+}
+
+func NewTrelloRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) TrelloRepository {
+	return TrelloRepository{
+		db:         db,
+		log:        log,
+		logService: logService,
+	}
+}
+
+// ImportBoard creates a board owned by the current user from a Trello board export.
+// Labels, lists, cards, checklists, check items and comments are imported, other Trello features are counted in the report.
+func (repo TrelloRepository) ImportBoard(context models.Context, trelloBoard *models.TrelloBoard) (*models.TrelloImportReport, int, error) {
+	if trelloBoard.Name == "" {
+		return nil, http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidTrelloExport"))
+	}
+
+	report := &models.TrelloImportReport{
+		Unmapped: map[string]int{},
+	}
+	now := time.Now()
+
+	tx := repo.db.Begin()
+
+	board := models.Board{
+		ID:     uuid.NewString(),
+		UserID: context.UserId,
+		Title:  trelloBoard.Name,
+	}
+	if trelloBoard.Closed {
+		board.ArchivedAt = &now
+	}
+	err := tx.Omit("BackgroundID", "Background", "Lists", "Labels", "CustomFields").Create(&board).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+	report.BoardID = board.ID
+
+	// creator is owner of the board
+	err = tx.Create(&models.BoardMember{
+		BoardID: board.ID,
+		UserID:  context.UserId,
+		Role:    models.BoardRoleOwner,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// labels
+	labelIds := map[string]string{}
+	for _, trelloLabel := range trelloBoard.Labels {
+		label := models.Label{
+			ID:      uuid.NewString(),
+			BoardID: board.ID,
+			Name:    trelloLabel.Name,
+			Color:   trelloLabelColor(trelloLabel.Color),
+		}
+		// Trello labels can have a color only
+		if label.Name == "" {
+			label.Name = trelloLabel.Color
+		}
+		err = tx.Create(&label).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, http.StatusInternalServerError, err
+		}
+		labelIds[trelloLabel.ID] = label.ID
+		report.Labels++
+	}
+
+	// lists, open ones first as only they are numbered in a board
+	trelloLists := trelloBoard.Lists
+	sort.SliceStable(trelloLists, func(i, j int) bool {
+		if trelloLists[i].Closed != trelloLists[j].Closed {
+			return !trelloLists[i].Closed
+		}
+		return trelloLists[i].Pos < trelloLists[j].Pos
+	})
+	listIds := map[string]string{}
+	listRanks := rank.Sequence(len(trelloLists))
+	for i, trelloList := range trelloLists {
+		list := models.List{
+			ID:      uuid.NewString(),
+			BoardID: board.ID,
+			Title:   trelloList.Name,
+			Rank:    listRanks[i],
+		}
+		if trelloList.Closed {
+			list.ArchivedAt = &now
+		}
+		err = tx.Omit("Cards").Create(&list).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, http.StatusInternalServerError, err
+		}
+		listIds[trelloList.ID] = list.ID
+		report.Lists++
+	}
+
+	// cards
+	trelloCards := trelloBoard.Cards
+	sort.SliceStable(trelloCards, func(i, j int) bool {
+		if trelloCards[i].Closed != trelloCards[j].Closed {
+			return !trelloCards[i].Closed
+		}
+		return trelloCards[i].Pos < trelloCards[j].Pos
+	})
+	cardIds := map[string]string{}
+	// ranks of the cards of each list, in order
+	cardCounts := map[string]int{}
+	for _, trelloCard := range trelloCards {
+		cardCounts[trelloCard.IDList]++
+	}
+	cardRanks := map[string][]string{}
+	for trelloListId, count := range cardCounts {
+		cardRanks[trelloListId] = rank.Sequence(count)
+	}
+	positions := map[string]int{}
+	for _, trelloCard := range trelloCards {
+		listId, ok := listIds[trelloCard.IDList]
+		if !ok {
+			report.Unmapped["orphanCards"]++
+			continue
+		}
+		card := models.Card{
+			ID:          uuid.NewString(),
+			ListID:      listId,
+			Title:       trelloCard.Name,
+			Description: trelloCard.Desc,
+			Rank:        cardRanks[trelloCard.IDList][positions[trelloCard.IDList]],
+			StartAt:     trelloCard.Start,
+			DueAt:       trelloCard.Due,
+		}
+		positions[trelloCard.IDList]++
+		// Trello only keeps a completion flag, the last activity is the closest date
+		if trelloCard.DueComplete {
+			completedAt := trelloCard.DateLastActivity
+			if completedAt.IsZero() {
+				completedAt = now
+			}
+			card.CompletedAt = &completedAt
+		}
+		if trelloCard.Closed {
+			archivedAt := trelloCard.DateLastActivity
+			if archivedAt.IsZero() {
+				archivedAt = now
+			}
+			card.ArchivedAt = &archivedAt
+		}
+		err = tx.Omit("Comments", "Checklists", "Labels", "Assignees", "CustomFieldValues").Create(&card).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, http.StatusInternalServerError, err
+		}
+		cardIds[trelloCard.ID] = card.ID
+		report.Cards++
+
+		for _, trelloLabelId := range trelloCard.IDLabels {
+			labelId, ok := labelIds[trelloLabelId]
+			if !ok {
+				report.Unmapped["orphanCardLabels"]++
+				continue
+			}
+			err = tx.Create(&models.CardLabel{CardID: card.ID, LabelID: labelId}).Error
+			if err != nil {
+				tx.Rollback()
+				return nil, http.StatusInternalServerError, err
+			}
+		}
+
+		report.Unmapped["cardMembers"] += len(trelloCard.IDMembers)
+		report.Unmapped["attachments"] += len(trelloCard.Attachments)
+		report.Unmapped["customFieldValues"] += len(trelloCard.CustomFieldItems)
+	}
+
+	// checklists
+	trelloChecklists := trelloBoard.Checklists
+	sort.SliceStable(trelloChecklists, func(i, j int) bool {
+		return trelloChecklists[i].Pos < trelloChecklists[j].Pos
+	})
+	for i, trelloChecklist := range trelloChecklists {
+		cardId, ok := cardIds[trelloChecklist.IDCard]
+		if !ok {
+			report.Unmapped["orphanChecklists"]++
+			continue
+		}
+		// checklists are sorted by creation date in boards, keep Trello order
+		checklist := models.Checklist{
+			ID:        uuid.NewString(),
+			CardID:    cardId,
+			Title:     trelloChecklist.Name,
+			CreatedAt: now.Add(-time.Duration(i) * time.Second),
+		}
+		err = tx.Omit("Items").Create(&checklist).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, http.StatusInternalServerError, err
+		}
+		report.Checklists++
+
+		checkItems := trelloChecklist.CheckItems
+		sort.SliceStable(checkItems, func(i, j int) bool {
+			return checkItems[i].Pos < checkItems[j].Pos
+		})
+		itemRanks := rank.Sequence(len(checkItems))
+		for j, checkItem := range checkItems {
+			item := models.ChecklistItem{
+				ID:          uuid.NewString(),
+				ChecklistID: checklist.ID,
+				Title:       checkItem.Name,
+				Rank:        itemRanks[j],
+				Checked:     checkItem.State == "complete",
+			}
+			err = tx.Create(&item).Error
+			if err != nil {
+				tx.Rollback()
+				return nil, http.StatusInternalServerError, err
+			}
+			report.ChecklistItems++
+		}
+	}
+
+	// comments, Trello members cannot be matched with users so comments are attributed to the current user
+	for _, action := range trelloBoard.Actions {
+		if action.Type != "commentCard" {
+			continue
+		}
+		cardId, ok := cardIds[action.Data.Card.ID]
+		if !ok {
+			report.Unmapped["orphanComments"]++
+			continue
+		}
+		comment := models.Comment{
+			ID:        uuid.NewString(),
+			CardID:    cardId,
+			UserID:    context.UserId,
+			Content:   action.Data.Text,
+			CreatedAt: action.Date,
+			UpdatedAt: action.Date,
+		}
+		err = tx.Create(&comment).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, http.StatusInternalServerError, err
+		}
+		report.Comments++
+		report.Unmapped["commentAuthors"]++
+	}
+
+	report.Unmapped["members"] = len(trelloBoard.Members)
+	report.Unmapped["customFields"] = len(trelloBoard.CustomFields)
+	if trelloBoard.Prefs.BackgroundImage != "" || trelloBoard.Prefs.BackgroundColor != "" {
+		report.Unmapped["background"] = 1
+	}
+	if trelloBoard.Desc != "" {
+		report.Unmapped["boardDescription"] = 1
+	}
+	// only report what was actually lost
+	for feature, count := range report.Unmapped {
+		if count == 0 {
+			delete(report.Unmapped, feature)
+		}
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		BoardID:        board.ID,
+		Action:         "importboard",
+		ActionTargetID: board.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, severity, err
+	}
+
+	tx.Commit()
+
+	return report, http.StatusCreated, nil
+}
+
+// trelloLabelColors are the colors of the Trello label palette
+var trelloLabelColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+// trelloLabelColor converts a Trello color name (e.g. "green" or "green_dark") to a CSS color, grey if unknown
+func trelloLabelColor(name string) string {
+	name, _, _ = strings.Cut(name, "_")
+	if color, ok := trelloLabelColors[name]; ok {
+		return color
+	}
+	return "#b3bac5"
+}
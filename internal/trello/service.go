@@ -0,0 +1,24 @@
+package trello
+
+import (
+	"trellode-go/internal/models"
+)
+
+type TrelloServiceInterface interface {
+	ImportBoard(models.Context, *models.TrelloBoard) (*models.TrelloImportReport, int, error)
+}
+
+type TrelloService struct {
+	repo TrelloRepositoryInterface
+}
+
+// NewTrelloService returns a service to import Trello boards
+func NewTrelloService(repo TrelloRepositoryInterface) TrelloService {
+	return TrelloService{
+		repo: repo,
+	}
+}
+
+func (s TrelloService) ImportBoard(context models.Context, trelloBoard *models.TrelloBoard) (*models.TrelloImportReport, int, error) {
+	return s.repo.ImportBoard(context, trelloBoard)
+}
@@ -0,0 +1,34 @@
+package blocked
+
+import (
+	"errors"
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+
+	"gorm.io/gorm"
+)
+
+// Check returns an error if the card cannot enter the target list because it is blocked by a card
+// that is neither completed nor in a done list
+func Check(context models.Context, db *gorm.DB, card *models.Card, targetList *models.List) (int, error) {
+	if !targetList.IsDone || !targetList.RefuseBlocked {
+		return http.StatusOK, nil
+	}
+
+	var blockers int64
+	err := db.Model(&models.CardLink{}).
+		Joins("JOIN cards ON cards.id = card_links.card_id").
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Where("card_links.type = ? AND card_links.linked_card_id = ?", models.CardLinkBlocks, card.ID).
+		Where("cards.completed_at IS NULL AND lists.is_done = ?", false).
+		Count(&blockers).Error
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if blockers > 0 {
+		return http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "CardBlocked"))
+	}
+
+	return http.StatusOK, nil
+}
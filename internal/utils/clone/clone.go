@@ -0,0 +1,337 @@
+package clone
+
+import (
+	"trellode-go/internal/member/access"
+	"trellode-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Mapping gives, for each board-level setting referenced by cards (labels, custom fields),
+// the ID to use on the copies for each source ID. Settings without a mapping are left out of the copies.
+type Mapping struct {
+	Labels       map[string]string
+	CustomFields map[string]string
+}
+
+// BoardContent copies the settings (labels, custom fields) and lists of source (with their cards, checklists and items)
+// into the board targetBoardId. Source must have been loaded with its children, only what has been loaded is copied.
+func BoardContent(tx *gorm.DB, source *models.Board, targetBoardId string, withComments bool) error {
+	mapping, err := BoardSettings(tx, source, targetBoardId)
+	if err != nil {
+		return err
+	}
+
+	for _, list := range source.Lists {
+		_, err := List(tx, &list, targetBoardId, list.Rank, withComments, mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BoardSettings copies the labels and custom fields of source into the board boardId and returns the mapping to their copies
+func BoardSettings(tx *gorm.DB, source *models.Board, boardId string) (Mapping, error) {
+	mapping := Mapping{
+		Labels:       map[string]string{},
+		CustomFields: map[string]string{},
+	}
+	for _, sourceLabel := range source.Labels {
+		label := models.Label{
+			ID:      uuid.NewString(),
+			BoardID: boardId,
+			Name:    sourceLabel.Name,
+			Color:   sourceLabel.Color,
+		}
+		err := tx.Create(&label).Error
+		if err != nil {
+			return mapping, err
+		}
+		mapping.Labels[sourceLabel.ID] = label.ID
+	}
+	for _, sourceField := range source.CustomFields {
+		field := models.CustomField{
+			ID:       uuid.NewString(),
+			BoardID:  boardId,
+			Name:     sourceField.Name,
+			Type:     sourceField.Type,
+			Options:  sourceField.Options,
+			Position: sourceField.Position,
+		}
+		err := tx.Create(&field).Error
+		if err != nil {
+			return mapping, err
+		}
+		mapping.CustomFields[sourceField.ID] = field.ID
+	}
+
+	return mapping, nil
+}
+
+// Match maps the settings of the board sourceBoardId to the settings of the board targetBoardId:
+// labels with the same name, custom fields with the same name and type
+func Match(tx *gorm.DB, sourceBoardId string, targetBoardId string) (Mapping, error) {
+	mapping := Mapping{
+		Labels:       map[string]string{},
+		CustomFields: map[string]string{},
+	}
+
+	sourceLabels := []models.Label{}
+	err := tx.Where("board_id = ?", sourceBoardId).Find(&sourceLabels).Error
+	if err != nil {
+		return mapping, err
+	}
+	targetLabels := []models.Label{}
+	err = tx.Where("board_id = ?", targetBoardId).Find(&targetLabels).Error
+	if err != nil {
+		return mapping, err
+	}
+	for _, sourceLabel := range sourceLabels {
+		for _, targetLabel := range targetLabels {
+			if sourceLabel.ID == targetLabel.ID || sourceLabel.Name == targetLabel.Name {
+				mapping.Labels[sourceLabel.ID] = targetLabel.ID
+				break
+			}
+		}
+	}
+
+	sourceFields := []models.CustomField{}
+	err = tx.Where("board_id = ?", sourceBoardId).Find(&sourceFields).Error
+	if err != nil {
+		return mapping, err
+	}
+	targetFields := []models.CustomField{}
+	err = tx.Where("board_id = ?", targetBoardId).Find(&targetFields).Error
+	if err != nil {
+		return mapping, err
+	}
+	for _, sourceField := range sourceFields {
+		for _, targetField := range targetFields {
+			if sourceField.ID == targetField.ID || (sourceField.Name == targetField.Name && sourceField.Type == targetField.Type) {
+				mapping.CustomFields[sourceField.ID] = targetField.ID
+				break
+			}
+		}
+	}
+
+	return mapping, nil
+}
+
+// MoveCards migrates the cards cardIds that moved to the board targetBoardId: their labels and custom field values
+// follow mapping (and are dropped without a match), their assignees who cannot access the board are unassigned
+func MoveCards(tx *gorm.DB, cardIds []string, targetBoardId string, mapping Mapping) error {
+	if len(cardIds) == 0 {
+		return nil
+	}
+
+	cardLabels := []models.CardLabel{}
+	err := tx.Where("card_id IN ?", cardIds).Find(&cardLabels).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Where("card_id IN ?", cardIds).Delete(&models.CardLabel{}).Error
+	if err != nil {
+		return err
+	}
+	for _, cardLabel := range cardLabels {
+		labelId, ok := mapping.Labels[cardLabel.LabelID]
+		if !ok {
+			continue
+		}
+		err = tx.Create(&models.CardLabel{CardID: cardLabel.CardID, LabelID: labelId}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	values := []models.CustomFieldValue{}
+	err = tx.Where("card_id IN ?", cardIds).Find(&values).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Where("card_id IN ?", cardIds).Delete(&models.CustomFieldValue{}).Error
+	if err != nil {
+		return err
+	}
+	for _, sourceValue := range values {
+		fieldId, ok := mapping.CustomFields[sourceValue.CustomFieldID]
+		if !ok {
+			continue
+		}
+		value := models.CustomFieldValue{
+			CardID:        sourceValue.CardID,
+			CustomFieldID: fieldId,
+			Value:         sourceValue.Value,
+			CreatedAt:     sourceValue.CreatedAt,
+		}
+		err = tx.Omit("CustomField").Create(&value).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	// notifications follow their cards
+	err = tx.Model(&models.Notification{}).Where("card_id IN ?", cardIds).Update("board_id", targetBoardId).Error
+	if err != nil {
+		return err
+	}
+
+	// assignees without a role on the target board are dropped
+	return tx.
+		Where("card_id IN ? AND user_id NOT IN (?)", cardIds, access.BoardUsers(tx, targetBoardId)).
+		Delete(&models.CardAssignee{}).Error
+}
+
+// List copies a list with its cards into the board boardId with the given rank and returns the new list,
+// cards keep their ranks
+func List(tx *gorm.DB, source *models.List, boardId string, rank string, withComments bool, mapping Mapping) (*models.List, error) {
+	list := models.List{
+		ID:      uuid.NewString(),
+		BoardID: boardId,
+		Title:   source.Title,
+		Rank:    rank,
+	}
+	err := tx.Omit("Cards").Create(&list).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, card := range source.Cards {
+		_, err := Card(tx, &card, list.ID, card.Rank, withComments, mapping)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &list, nil
+}
+
+// Card copies a card with its checklists, labels, custom field values, color cover (and comments if asked) into the list listId
+// with the given rank and returns the new card
+func Card(tx *gorm.DB, source *models.Card, listId string, rank string, withComments bool, mapping Mapping) (*models.Card, error) {
+	card := models.Card{
+		ID:          uuid.NewString(),
+		ListID:      listId,
+		Title:       source.Title,
+		Description: source.Description,
+		Rank:        rank,
+		StartAt:     source.StartAt,
+		DueAt:       source.DueAt,
+		CompletedAt: source.CompletedAt,
+	}
+	// attachments are not copied, neither are image covers
+	if source.Cover.AttachmentID == nil {
+		card.Cover = source.Cover
+	}
+	err := tx.Omit("Comments", "Checklists", "Labels", "Assignees", "CustomFieldValues").Create(&card).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, label := range source.Labels {
+		labelId, ok := mapping.Labels[label.ID]
+		if !ok {
+			continue
+		}
+		err := tx.Create(&models.CardLabel{CardID: card.ID, LabelID: labelId}).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, sourceValue := range source.CustomFieldValues {
+		fieldId, ok := mapping.CustomFields[sourceValue.CustomFieldID]
+		if !ok {
+			continue
+		}
+		value := models.CustomFieldValue{
+			CardID:        card.ID,
+			CustomFieldID: fieldId,
+			Value:         sourceValue.Value,
+		}
+		err := tx.Omit("CustomField").Create(&value).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, checklist := range source.Checklists {
+		_, err := Checklist(tx, &checklist, card.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if withComments {
+		// comments are loaded either flat or as threads, top-level comments are copied before replies
+		comments := []models.Comment{}
+		replies := []models.Comment{}
+		for _, sourceComment := range source.Comments {
+			if sourceComment.ParentID == nil {
+				comments = append(comments, sourceComment)
+			} else {
+				replies = append(replies, sourceComment)
+			}
+			replies = append(replies, sourceComment.Replies...)
+		}
+		ids := map[string]string{}
+		for _, sourceComment := range append(comments, replies...) {
+			// keep original dates so that comments stay in the same order
+			comment := models.Comment{
+				ID:        uuid.NewString(),
+				CardID:    card.ID,
+				UserID:    sourceComment.UserID,
+				Content:   sourceComment.Content,
+				CreatedAt: sourceComment.CreatedAt,
+				UpdatedAt: sourceComment.UpdatedAt,
+				EditedAt:  sourceComment.EditedAt,
+				DeletedAt: sourceComment.DeletedAt,
+			}
+			if sourceComment.ParentID != nil {
+				parentId, ok := ids[*sourceComment.ParentID]
+				if !ok {
+					continue
+				}
+				comment.ParentID = &parentId
+			}
+			err := tx.Omit("Replies").Create(&comment).Error
+			if err != nil {
+				return nil, err
+			}
+			ids[sourceComment.ID] = comment.ID
+		}
+	}
+
+	return &card, nil
+}
+
+// Checklist copies a checklist with its items into the card cardId and returns the new checklist
+func Checklist(tx *gorm.DB, source *models.Checklist, cardId string) (*models.Checklist, error) {
+	checklist := models.Checklist{
+		ID:     uuid.NewString(),
+		CardID: cardId,
+		Title:  source.Title,
+	}
+	err := tx.Omit("Items").Create(&checklist).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sourceItem := range source.Items {
+		item := models.ChecklistItem{
+			ID:          uuid.NewString(),
+			ChecklistID: checklist.ID,
+			Title:       sourceItem.Title,
+			Rank:        sourceItem.Rank,
+			Checked:     sourceItem.Checked,
+		}
+		err := tx.Create(&item).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &checklist, nil
+}
@@ -0,0 +1,31 @@
+package commentcount
+
+import (
+	"trellode-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Load returns how many comments, replies included and deleted ones excluded, the cards selected by
+// the subquery cardIds have, by card id. Cards without comments are missing.
+func Load(db *gorm.DB, cardIds string, args ...interface{}) (map[string]int, error) {
+	counts := []struct {
+		CardID string
+		Count  int
+	}{}
+	err := db.Model(&models.Comment{}).
+		Select("card_id, COUNT(*) AS count").
+		Where("card_id IN ("+cardIds+") AND deleted_at IS NULL", args...).
+		Group("card_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	countOfCard := map[string]int{}
+	for _, count := range counts {
+		countOfCard[count.CardID] = count.Count
+	}
+
+	return countOfCard, nil
+}
@@ -0,0 +1,119 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"strconv"
+	"strings"
+
+	"github.com/nfnt/resize"
+)
+
+// Thumbnail decodes an image (JPEG, PNG or GIF), resizes it to width pixels, preserving its aspect ratio,
+// and returns it with its average color
+func Thumbnail(data []byte, width uint) (image.Image, color.Color, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resizedImg := resize.Resize(width, 0, img, resize.Lanczos3)
+
+	return resizedImg, AverageColor(resizedImg), nil
+}
+
+// JPEGDataURL encodes an image as a base64 JPEG data URL
+func JPEGDataURL(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80})
+	if err != nil {
+		return "", err
+	}
+
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func AverageColor(img image.Image) color.Color {
+	bounds := img.Bounds()
+	var rTotal, gTotal, bTotal, count uint32
+
+	// Iterate over each pixel
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			rTotal += r >> 8
+			gTotal += g >> 8
+			bTotal += b >> 8
+			count++
+		}
+	}
+
+	// Calculate average values
+	avgR := uint8(rTotal / count)
+	avgG := uint8(gTotal / count)
+	avgB := uint8(bTotal / count)
+
+	return color.RGBA{R: avgR, G: avgG, B: avgB, A: 255}
+}
+
+// ContrastColor returns the CSS color of a text readable on a background of color c: black on light colors, white on dark ones
+func ContrastColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	// perceived brightness (ITU-R BT.601), between 0 and 255
+	brightness := (299*float64(r>>8) + 587*float64(g>>8) + 114*float64(b>>8)) / 1000
+	if brightness > 150 {
+		return "#000000"
+	}
+	return "#ffffff"
+}
+
+// ParseHexColor parses a CSS color formatted as #RRGGBB
+func ParseHexColor(s string) (color.Color, error) {
+	// Remove the '#' if it exists
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return nil, fmt.Errorf("invalid color %q, expected #RRGGBB", s)
+	}
+
+	var r, g, b, a uint8
+	var err error
+
+	// #RRGGBB
+	r, err = parseHexByte(s[0:2])
+	if err != nil {
+		return nil, err
+	}
+	g, err = parseHexByte(s[2:4])
+	if err != nil {
+		return nil, err
+	}
+	b, err = parseHexByte(s[4:6])
+	if err != nil {
+		return nil, err
+	}
+	a = 255 // fully opaque
+
+	return color.RGBA{R: r, G: g, B: b, A: a}, nil
+}
+
+func parseHexByte(s string) (uint8, error) {
+	v, err := strconv.ParseUint(s, 16, 8)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(v), nil
+}
+
+// ToCSS converts a color.Color to a CSS color string.
+//
+// It takes a color.Color as a parameter and returns a string representing the CSS color value.
+func ToCSS(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", uint8(r>>8), uint8(g>>8), uint8(b>>8))
+}
@@ -0,0 +1,47 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContrastColor(t *testing.T) {
+	assert.Equal(t, "#000000", ContrastColor(color.RGBA{R: 0xf2, G: 0xd6, B: 0x00, A: 0xff}))
+	assert.Equal(t, "#ffffff", ContrastColor(color.RGBA{R: 0x00, G: 0x79, B: 0xbf, A: 0xff}))
+}
+
+func TestParseHexColor(t *testing.T) {
+	c, err := ParseHexColor("#61BD4F")
+	assert.NoError(t, err)
+	assert.Equal(t, "#61bd4f", ToCSS(c))
+
+	_, err = ParseHexColor("#61bd4")
+	assert.Error(t, err)
+	_, err = ParseHexColor("#61bd4g")
+	assert.Error(t, err)
+}
+
+func TestThumbnail(t *testing.T) {
+	source := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for x := 0; x < 640; x++ {
+		for y := 0; y < 480; y++ {
+			source.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+		}
+	}
+	var buffer bytes.Buffer
+	assert.NoError(t, png.Encode(&buffer, source))
+
+	thumbnail, average, err := Thumbnail(buffer.Bytes(), 320)
+	assert.NoError(t, err)
+	assert.Equal(t, 320, thumbnail.Bounds().Dx())
+	assert.Equal(t, 240, thumbnail.Bounds().Dy())
+	assert.Equal(t, "#ff0000", ToCSS(average))
+
+	_, _, err = Thumbnail([]byte("not an image"), 320)
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package mention
+
+import (
+	"regexp"
+	"strings"
+	"trellode-go/internal/member/access"
+	"trellode-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// a mention is @ followed by an email or by firstname.lastname, not preceded by a letter, a digit, a dot or an @
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\p{L}\d_.@])@([\w.+-]+@[\w-]+(?:\.[\w-]+)+|[\p{L}\d_'-]+(?:\.[\p{L}\d_'-]+)+)`)
+
+// Parse returns the emails and firstname.lastname mentioned in text, lowercased and without duplicates
+func Parse(text string) []string {
+	mentions := []string{}
+	found := map[string]bool{}
+	for _, match := range mentionRegexp.FindAllStringSubmatch(text, -1) {
+		// a mention at the end of a sentence
+		mention := strings.ToLower(strings.TrimRight(match[1], ".'-"))
+		if !found[mention] {
+			found[mention] = true
+			mentions = append(mentions, mention)
+		}
+	}
+
+	return mentions
+}
+
+// Notify creates in tx a copy of notification for each user mentioned in text but not in previousText,
+// except its actor and users who cannot see the board of the notification
+func Notify(tx *gorm.DB, text string, previousText string, notification models.Notification) error {
+	previous := map[string]bool{}
+	for _, mention := range Parse(previousText) {
+		previous[mention] = true
+	}
+	mentions := []string{}
+	for _, mention := range Parse(text) {
+		if !previous[mention] {
+			mentions = append(mentions, mention)
+		}
+	}
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	// names are compared without their spaces, which cannot be part of a mention
+	users := []models.User{}
+	err := tx.
+		Where("LOWER(email) IN ? OR LOWER(REPLACE(CONCAT(firstname, '.', lastname), ' ', '')) IN ?", mentions, mentions).
+		Where("id <> ? AND id IN (?)", notification.ActorID, access.BoardUsers(tx, notification.BoardID)).
+		Find(&users).Error
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		userNotification := notification
+		userNotification.ID = uuid.NewString()
+		userNotification.UserID = user.ID
+		err = tx.Omit("Actor", "Card").Create(&userNotification).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
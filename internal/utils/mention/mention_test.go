@@ -0,0 +1,26 @@
+package mention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		mentions []string
+	}{
+		{"", []string{}},
+		{"no mention here", []string{}},
+		{"@John.Doe can you check?", []string{"john.doe"}},
+		{"ask @jane.smith@example.com and @john.doe.", []string{"jane.smith@example.com", "john.doe"}},
+		{"@Élodie.Durand, @jean-pierre.martin: done", []string{"élodie.durand", "jean-pierre.martin"}},
+		{"@john.doe @JOHN.DOE", []string{"john.doe"}},
+		{"mail me at me@john.doe or @john", []string{}},
+		{"(@john.doe)", []string{"john.doe"}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.mentions, Parse(test.text), test.text)
+	}
+}
@@ -0,0 +1,177 @@
+// Package rank generates lexicographic ranks to order lists, cards and checklist items:
+// an item is moved by giving it a rank between the ranks of its new neighbours, without touching them.
+package rank
+
+import "strings"
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// MaxLength is the length above which the ranks of a group of siblings should be rebalanced
+const MaxLength = 32
+
+// Between returns a rank sorting strictly between before and after, an empty before standing for the beginning
+// and an empty after for the end. Ranks never end with the lowest digit so that there is always room before them.
+// When after does not sort after before (duplicate ranks), the returned rank only sorts after before.
+func Between(before string, after string) string {
+	if after != "" && before >= after {
+		after = ""
+	}
+	return midpoint(before, after)
+}
+
+func midpoint(a string, b string) string {
+	if b != "" {
+		// keep the common prefix, a being padded with the lowest digit
+		n := 0
+		for n < len(b) && digitAt(a, n) == strings.IndexByte(digits, b[n]) {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	digitA := digitAt(a, 0)
+	digitB := base
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB)/2])
+	}
+	// consecutive digits
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[digitA]) + midpoint(suffix(a, 1), "")
+}
+
+func digitAt(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	return strings.IndexByte(digits, s[i])
+}
+
+func suffix(s string, i int) string {
+	if i >= len(s) {
+		return ""
+	}
+	return s[i:]
+}
+
+// Sequence returns n evenly spaced, increasing ranks
+func Sequence(n int) []string {
+	width := 1
+	capacity := base
+	for capacity <= n+1 {
+		width++
+		capacity *= base
+	}
+	step := capacity / (n + 1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := (i + 1) * step
+		rank := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			rank[j] = digits[value%base]
+			value /= base
+		}
+		ranks[i] = strings.TrimRight(string(rank), digits[:1])
+	}
+
+	return ranks
+}
+
+// Reorder gives new ranks to the items whose ranks, in their new order, are given by ranks, so that they sort
+// in this order. As many items as possible keep their rank: the returned map gives the new ranks by index
+// of the items to update, a single item moved by a drag and drop gets a single new rank.
+func Reorder(ranks []string) map[int]string {
+	kept := increasing(ranks)
+
+	changed := map[int]string{}
+	previous := ""
+	next := 0
+	for i, rank := range ranks {
+		if kept[i] {
+			previous = rank
+			continue
+		}
+		// rank of the next item keeping its rank
+		if next <= i {
+			next = i + 1
+			for next < len(ranks) && !kept[next] {
+				next++
+			}
+		}
+		after := ""
+		if next < len(ranks) {
+			after = ranks[next]
+		}
+		previous = Between(previous, after)
+		changed[i] = previous
+	}
+
+	return changed
+}
+
+// increasing returns the indexes of a longest strictly increasing subsequence of non-empty ranks
+func increasing(ranks []string) map[int]bool {
+	// tails[k] is the index of the smallest last rank of an increasing subsequence of length k+1
+	tails := []int{}
+	parents := make([]int, len(ranks))
+	for i, rank := range ranks {
+		parents[i] = -1
+		if rank == "" {
+			continue
+		}
+		low, high := 0, len(tails)
+		for low < high {
+			middle := (low + high) / 2
+			if ranks[tails[middle]] < rank {
+				low = middle + 1
+			} else {
+				high = middle
+			}
+		}
+		if low > 0 {
+			parents[i] = tails[low-1]
+		}
+		if low == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[low] = i
+		}
+	}
+
+	kept := map[int]bool{}
+	if len(tails) == 0 {
+		return kept
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = parents[i] {
+		kept[i] = true
+	}
+
+	return kept
+}
+
+// At returns the rank to give to an item inserted at index (from 0) among siblings ordered by their ranks,
+// at the end when index is out of range
+func At(ranks []string, index int) string {
+	if index < 0 || index > len(ranks) {
+		index = len(ranks)
+	}
+	before := ""
+	if index > 0 {
+		before = ranks[index-1]
+	}
+	after := ""
+	if index < len(ranks) {
+		after = ranks[index]
+	}
+
+	return Between(before, after)
+}
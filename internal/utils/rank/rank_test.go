@@ -0,0 +1,117 @@
+package rank
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetween(t *testing.T) {
+	cases := [][2]string{
+		{"", ""},
+		{"", "1"},
+		{"", "01"},
+		{"i", ""},
+		{"z", ""},
+		{"zz", ""},
+		{"a", "b"},
+		{"a", "a1"},
+		{"a1", "a2"},
+		{"az", "b"},
+		{"0001", "0002"},
+		{"h", "hzz"},
+	}
+	for _, c := range cases {
+		rank := Between(c[0], c[1])
+		assert.Greater(t, rank, c[0], "between %q and %q", c[0], c[1])
+		if c[1] != "" {
+			assert.Less(t, rank, c[1], "between %q and %q", c[0], c[1])
+		}
+		assert.NotEqual(t, byte('0'), rank[len(rank)-1], "between %q and %q", c[0], c[1])
+	}
+
+	// duplicate ranks
+	assert.Greater(t, Between("b", "b"), "b")
+}
+
+func TestBetweenRepeated(t *testing.T) {
+	// always inserting at the same place makes ranks grow slowly
+	before, after := "", "i"
+	for i := 0; i < 100; i++ {
+		rank := Between(before, after)
+		assert.Greater(t, rank, before)
+		assert.Less(t, rank, after)
+		after = rank
+	}
+	assert.LessOrEqual(t, len(after), MaxLength)
+
+	before, after = "i", ""
+	for i := 0; i < 100; i++ {
+		rank := Between(before, after)
+		assert.Greater(t, rank, before)
+		before = rank
+	}
+	assert.LessOrEqual(t, len(before), MaxLength)
+}
+
+func TestSequence(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 35, 36, 300, 2000} {
+		ranks := Sequence(n)
+		assert.Len(t, ranks, n)
+		assert.True(t, sort.SliceIsSorted(ranks, func(i, j int) bool { return ranks[i] < ranks[j] }))
+		for i := 1; i < n; i++ {
+			assert.NotEqual(t, ranks[i-1], ranks[i])
+		}
+		for _, rank := range ranks {
+			assert.NotEqual(t, byte('0'), rank[len(rank)-1])
+		}
+	}
+}
+
+func TestAt(t *testing.T) {
+	ranks := Sequence(3)
+	assert.Less(t, At(ranks, 0), ranks[0])
+	assert.Greater(t, At(ranks, 1), ranks[0])
+	assert.Less(t, At(ranks, 1), ranks[1])
+	assert.Greater(t, At(ranks, 3), ranks[2])
+	assert.Greater(t, At(ranks, 10), ranks[2])
+	assert.Equal(t, Between("", ""), At(nil, 0))
+}
+
+func TestReorder(t *testing.T) {
+	ranks := Sequence(10)
+
+	// move the last item first
+	moved := append([]string{ranks[9]}, ranks[:9]...)
+	changed := Reorder(moved)
+	assert.Len(t, changed, 1)
+	assert.Less(t, changed[0], ranks[0])
+
+	// reverse everything
+	reversed := make([]string, len(ranks))
+	for i := range ranks {
+		reversed[i] = ranks[len(ranks)-1-i]
+	}
+	changed = Reorder(reversed)
+	assert.Len(t, changed, 9)
+	assertSorted(t, reversed, changed)
+
+	// items without rank
+	changed = Reorder([]string{"", "", ranks[3], ""})
+	assert.Len(t, changed, 3)
+	assertSorted(t, []string{"", "", ranks[3], ""}, changed)
+}
+
+func assertSorted(t *testing.T, ranks []string, changed map[int]string) {
+	result := make([]string, len(ranks))
+	for i, rank := range ranks {
+		result[i] = rank
+		if newRank, ok := changed[i]; ok {
+			result[i] = newRank
+		}
+	}
+	for i := 1; i < len(result); i++ {
+		assert.Less(t, result[i-1], result[i])
+	}
+}
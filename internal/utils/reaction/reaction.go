@@ -0,0 +1,150 @@
+package reaction
+
+import (
+	"errors"
+	"time"
+	"trellode-go/internal/models"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// maxEmojiLength is the length in bytes of the longest accepted emoji, family and flag sequences included
+const maxEmojiLength = 64
+
+// IsEmoji tells whether s is a single emoji or emoji sequence (skin tones, joined emojis, flags, keycaps)
+func IsEmoji(s string) bool {
+	if s == "" || len(s) > maxEmojiLength || !utf8.ValidString(s) {
+		return false
+	}
+
+	symbols := 0
+	for i, r := range s {
+		switch {
+		case unicode.Is(unicode.So, r) || (r >= 0x1F1E6 && r <= 0x1F1FF):
+			// pictographs and regional indicators
+			symbols++
+		case r == 0x200D || r == 0xFE0E || r == 0xFE0F || r == 0x20E3 || unicode.Is(unicode.Sk, r) || (r >= 0xE0020 && r <= 0xE007F):
+			// joiners, variation selectors, keycaps, skin tones and tags only complete a pictograph
+			if symbols == 0 && r != 0xFE0F && r != 0x20E3 {
+				return false
+			}
+		case i == 0 && (r == '#' || r == '*' || (r >= '0' && r <= '9')):
+			// keycap base
+		default:
+			return false
+		}
+	}
+	if symbols == 0 {
+		// keycaps are the only sequence without a pictograph
+		last, _ := utf8.DecodeLastRuneInString(s)
+		return last == 0x20E3
+	}
+
+	return true
+}
+
+// Toggle puts the emoji of the user on the target, or removes it when the user already put it, and tells whether it was put
+func Toggle(tx *gorm.DB, targetType string, targetId string, userId string, emoji string) (bool, error) {
+	var existing models.Reaction
+	err := tx.Where("target_type = ? AND target_id = ? AND user_id = ? AND emoji = ?", targetType, targetId, userId, emoji).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if existing.UserID != "" {
+		return false, tx.Where("target_type = ? AND target_id = ? AND user_id = ? AND emoji = ?", targetType, targetId, userId, emoji).Delete(&models.Reaction{}).Error
+	}
+
+	return true, tx.Create(&models.Reaction{
+		TargetType: targetType,
+		TargetID:   targetId,
+		UserID:     userId,
+		Emoji:      emoji,
+		CreatedAt:  time.Now(),
+	}).Error
+}
+
+// Load returns the summaries of the reactions on the targets, by target ID
+func Load(db *gorm.DB, targetType string, targetIds []string, userId string) (map[string][]models.ReactionSummary, error) {
+	summaries := map[string][]models.ReactionSummary{}
+	if len(targetIds) == 0 {
+		return summaries, nil
+	}
+	for _, targetId := range targetIds {
+		summaries[targetId] = []models.ReactionSummary{}
+	}
+
+	reactions := []models.Reaction{}
+	err := db.
+		Where("target_type = ? AND target_id IN ?", targetType, targetIds).
+		Order("created_at ASC").
+		Find(&reactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byTarget := map[string][]models.Reaction{}
+	for _, reaction := range reactions {
+		byTarget[reaction.TargetID] = append(byTarget[reaction.TargetID], reaction)
+	}
+	for targetId, targetReactions := range byTarget {
+		summaries[targetId] = Summarize(targetReactions, userId)
+	}
+
+	return summaries, nil
+}
+
+// LoadComments sets the reactions of the comments and of their replies
+func LoadComments(db *gorm.DB, comments []*models.Comment, userId string) error {
+	ids := []string{}
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+		for _, reply := range comment.Replies {
+			ids = append(ids, reply.ID)
+		}
+	}
+	summaries, err := Load(db, models.ReactionTargetComment, ids, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		comment.Reactions = summaries[comment.ID]
+		for i := range comment.Replies {
+			comment.Replies[i].Reactions = summaries[comment.Replies[i].ID]
+		}
+	}
+
+	return nil
+}
+
+// Summarize counts the reactions by emoji, in the order the emojis were first put
+func Summarize(reactions []models.Reaction, userId string) []models.ReactionSummary {
+	summaries := []models.ReactionSummary{}
+	index := map[string]int{}
+	for _, reaction := range reactions {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(summaries)
+			index[reaction.Emoji] = i
+			summaries = append(summaries, models.ReactionSummary{Emoji: reaction.Emoji})
+		}
+		summaries[i].Count++
+		if reaction.UserID == userId {
+			summaries[i].ReactedByMe = true
+		}
+	}
+
+	return summaries
+}
+
+// DeleteOfCards removes in tx the reactions on the cards cardIds (an SQL subquery or placeholder) and on their comments
+func DeleteOfCards(tx *gorm.DB, cardIds string, args ...interface{}) error {
+	err := tx.Where("target_type = ? AND target_id IN ("+cardIds+")", append([]interface{}{models.ReactionTargetCard}, args...)...).Delete(&models.Reaction{}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("target_type = ? AND target_id IN (SELECT id FROM comments WHERE card_id IN ("+cardIds+"))", append([]interface{}{models.ReactionTargetComment}, args...)...).Delete(&models.Reaction{}).Error
+}
@@ -0,0 +1,35 @@
+package reaction
+
+import (
+	"testing"
+	"trellode-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEmoji(t *testing.T) {
+	valid := []string{"👍", "❤️", "👍🏽", "👨‍👩‍👧", "🇫🇷", "1️⃣", "#️⃣", "✅"}
+	for _, emoji := range valid {
+		assert.True(t, IsEmoji(emoji), emoji)
+	}
+	invalid := []string{"", "a", "+1", "👍 ", "1", "🏽", ":smile:", "👍a"}
+	for _, emoji := range invalid {
+		assert.False(t, IsEmoji(emoji), emoji)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	reactions := []models.Reaction{
+		{UserID: "1", Emoji: "👍"},
+		{UserID: "2", Emoji: "🎉"},
+		{UserID: "2", Emoji: "👍"},
+		{UserID: "3", Emoji: "👍"},
+	}
+
+	assert.Equal(t, []models.ReactionSummary{
+		{Emoji: "👍", Count: 3, ReactedByMe: true},
+		{Emoji: "🎉", Count: 1, ReactedByMe: true},
+	}, Summarize(reactions, "2"))
+	assert.Equal(t, []models.ReactionSummary{{Emoji: "👍", Count: 3, ReactedByMe: true}, {Emoji: "🎉", Count: 1}}, Summarize(reactions, "1"))
+	assert.Equal(t, []models.ReactionSummary{}, Summarize(nil, "1"))
+}
@@ -0,0 +1,183 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+
+	// MaxInterval is the largest interval between occurrences, in days, weeks or months
+	MaxInterval = 100
+)
+
+var weekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Rule is a subset of iCalendar RRULE: a frequency, an interval, the weekdays of a weekly rule
+// and the day of the month of a monthly rule
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday // weekly rules only, the weekday of the start when empty
+	ByMonthDay int            // monthly rules only, the day of the start when 0
+}
+
+// Parse parses "daily", "weekly", "monthly" or an RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"
+// (the "RRULE:" prefix is optional, only FREQ, INTERVAL, BYDAY and BYMONTHDAY are supported)
+func Parse(s string) (Rule, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	rule := Rule{Interval: 1}
+	switch s {
+	case FreqDaily, FreqWeekly, FreqMonthly:
+		rule.Freq = s
+		return rule, nil
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		switch name {
+		case "FREQ":
+			rule.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > MaxInterval {
+				return Rule{}, fmt.Errorf("invalid recurrence interval %q", value)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := parseWeekday(day)
+				if !ok {
+					return Rule{}, fmt.Errorf("invalid recurrence weekday %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil || day < 1 || day > 31 {
+				return Rule{}, fmt.Errorf("invalid recurrence day of month %q", value)
+			}
+			rule.ByMonthDay = day
+		default:
+			return Rule{}, fmt.Errorf("unsupported recurrence rule part %q", name)
+		}
+	}
+
+	switch rule.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly:
+	default:
+		return Rule{}, fmt.Errorf("invalid recurrence frequency %q", rule.Freq)
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != FreqWeekly {
+		return Rule{}, errors.New("BYDAY is only supported by weekly recurrences")
+	}
+	if rule.ByMonthDay > 0 && rule.Freq != FreqMonthly {
+		return Rule{}, errors.New("BYMONTHDAY is only supported by monthly recurrences")
+	}
+
+	return rule, nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for i, weekday := range weekdays {
+		if weekday == s {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// String returns the rule as an RRULE
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := []string{}
+		for _, weekday := range r.ByDay {
+			days = append(days, weekdays[weekday])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after after (excluded) of a rule starting at start, occurrences are at the time
+// of day of start in its location. The zero time is returned when there is none, e.g. on the 31st of every 12 months from June.
+func (r Rule) Next(start time.Time, after time.Time) time.Time {
+	loc := start.Location()
+	first := start
+	if after.After(start) {
+		first = after.In(loc)
+	}
+	startDay := dayNumber(start)
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	// every occurrence pattern repeats within two years of intervals
+	for i := 0; i <= 2*366*interval; i++ {
+		day := time.Date(first.Year(), first.Month(), first.Day()+i, start.Hour(), start.Minute(), start.Second(), 0, loc)
+		if !r.matches(start, startDay, day) || day.Before(start) || !day.After(after) {
+			continue
+		}
+		return day
+	}
+
+	return time.Time{}
+}
+
+func (r Rule) matches(start time.Time, startDay int, day time.Time) bool {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		return (dayNumber(day)-startDay)%interval == 0
+	case FreqWeekly:
+		byDay := r.ByDay
+		if len(byDay) == 0 {
+			byDay = []time.Weekday{start.Weekday()}
+		}
+		found := false
+		for _, weekday := range byDay {
+			if day.Weekday() == weekday {
+				found = true
+			}
+		}
+		// weeks start on monday
+		startMonday := startDay - (int(start.Weekday())+6)%7
+		dayMonday := dayNumber(day) - (int(day.Weekday())+6)%7
+		return found && ((dayMonday-startMonday)/7)%interval == 0
+	case FreqMonthly:
+		monthDay := r.ByMonthDay
+		if monthDay == 0 {
+			monthDay = start.Day()
+		}
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		return day.Day() == monthDay && months%interval == 0
+	}
+
+	return false
+}
+
+// dayNumber returns the number of days since the epoch of the date of t
+func dayNumber(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
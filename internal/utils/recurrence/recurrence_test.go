@@ -0,0 +1,45 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("weekly")
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY", rule.String())
+
+	rule, err = Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH")
+	assert.NoError(t, err)
+	assert.Equal(t, Rule{Freq: FreqWeekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Thursday}}, rule)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", rule.String())
+
+	for _, invalid := range []string{"", "yearly", "FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;BYDAY=XX", "FREQ=MONTHLY;BYMONTHDAY=32", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;COUNT=3"} {
+		_, err = Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a monday
+	start := time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC)
+
+	daily, _ := Parse("FREQ=DAILY;INTERVAL=3")
+	assert.Equal(t, start, daily.Next(start, start.Add(-time.Second)))
+	assert.Equal(t, time.Date(2024, 1, 4, 8, 30, 0, 0, time.UTC), daily.Next(start, start))
+	assert.Equal(t, time.Date(2024, 1, 7, 8, 30, 0, 0, time.UTC), daily.Next(start, time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC)))
+
+	weekly, _ := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH")
+	assert.Equal(t, time.Date(2024, 1, 4, 8, 30, 0, 0, time.UTC), weekly.Next(start, start))
+	assert.Equal(t, time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC), weekly.Next(start, time.Date(2024, 1, 4, 8, 30, 0, 0, time.UTC)))
+
+	monthly, _ := Parse("FREQ=MONTHLY;BYMONTHDAY=31")
+	assert.Equal(t, time.Date(2024, 1, 31, 8, 30, 0, 0, time.UTC), monthly.Next(start, start))
+	assert.Equal(t, time.Date(2024, 3, 31, 8, 30, 0, 0, time.UTC), monthly.Next(start, time.Date(2024, 1, 31, 8, 30, 0, 0, time.UTC)))
+
+	never, _ := Parse("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=31")
+	assert.True(t, never.Next(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), start).IsZero())
+}
@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Interval returns the duration set in the environment variable name, defaultInterval when it is not set
+// and 0 when it is "none"
+func Interval(name string, defaultInterval time.Duration) (time.Duration, error) {
+	switch os.Getenv(name) {
+	case "":
+		return defaultInterval, nil
+	case "none":
+		return 0, nil
+	}
+
+	interval, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		return 0, errors.New(name + " must be positive")
+	}
+
+	return interval, nil
+}
+
+// Task is a job run periodically in the background
+type Task struct {
+	Interval time.Duration                    // 0 disables the task
+	Job      func(now time.Time) (int, error) // returns how many things it did
+	Disabled string                           // logged when the task is disabled
+	Failed   string                           // logged before the error of a failed run
+	Done     string                           // format logging how many things a run did, when there are some
+}
+
+// Run runs the job at once then every interval until ctx is done
+func (t Task) Run(ctx context.Context, log *zap.Logger) {
+	if t.Interval == 0 {
+		log.Info(t.Disabled)
+		return
+	}
+
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+	for {
+		count, err := t.Job(time.Now())
+		if err != nil {
+			log.Error(t.Failed + ": " + err.Error())
+		}
+		if count > 0 {
+			log.Info(fmt.Sprintf(t.Done, count))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
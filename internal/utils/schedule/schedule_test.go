@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestInterval(t *testing.T) {
+	interval, err := Interval("TEST_INTERVAL", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, interval)
+
+	t.Setenv("TEST_INTERVAL", "none")
+	interval, err = Interval("TEST_INTERVAL", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), interval)
+
+	t.Setenv("TEST_INTERVAL", "90s")
+	interval, err = Interval("TEST_INTERVAL", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, interval)
+
+	for _, value := range []string{"-1m", "0s", "often"} {
+		t.Setenv("TEST_INTERVAL", value)
+		_, err = Interval("TEST_INTERVAL", time.Minute)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestTaskRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	task := Task{
+		Interval: time.Hour,
+		Job: func(now time.Time) (int, error) {
+			runs++
+			cancel()
+			return 1, nil
+		},
+	}
+	// the job runs at once, then the task stops with its context
+	task.Run(ctx, zap.NewNop())
+	assert.Equal(t, 1, runs)
+
+	task.Interval = 0
+	task.Run(context.Background(), zap.NewNop())
+	assert.Equal(t, 1, runs)
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotFound is returned when getting an object which does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores binary objects (such as card attachments) identified by a key made of slash separated parts
+type Storage interface {
+	Put(key string, data io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// NewStorageFromEnv returns the storage configured with the environment variables:
+// STORAGE_BACKEND ("local", the default, or "s3"), STORAGE_PATH for the local backend and
+// S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY for the s3 backend
+func NewStorageFromEnv() (Storage, error) {
+	switch os.Getenv("STORAGE_BACKEND") {
+	case "", "local":
+		path := os.Getenv("STORAGE_PATH")
+		if path == "" {
+			path = "/home/trellode/attachments"
+		}
+		return NewLocalStorage(path), nil
+	case "s3":
+		if os.Getenv("S3_ENDPOINT") == "" || os.Getenv("S3_BUCKET") == "" {
+			return nil, errors.New("S3_ENDPOINT and S3_BUCKET are required by the s3 storage backend")
+		}
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return NewS3Storage(os.Getenv("S3_ENDPOINT"), region, os.Getenv("S3_BUCKET"), os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY")), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %s", os.Getenv("STORAGE_BACKEND"))
+	}
+}
@@ -1,7 +1,11 @@
 package tools
 
 import (
+	"encoding/base64"
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -18,14 +22,19 @@ func RemoveDuplicateStr(strSlice []string) []string {
 	return list
 }
 
+// MaxPageSize is the maximum number of results of a page
+const MaxPageSize = 100
+
+// DefaultCursorPageSize is the number of results of a page when browsing with a cursor without a page size
+const DefaultCursorPageSize = 20
+
 func CheckPagination(pageSize int, pageIndex int) (int, int, int) {
 	if pageSize <= 0 {
 		pageSize = -1
 		pageIndex = 0
 	}
-	// max page size is 100
-	if pageSize > 100 {
-		pageSize = 100
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
 	}
 	var offset int
 	offset = pageSize * pageIndex
@@ -36,6 +45,46 @@ func CheckPagination(pageSize int, pageIndex int) (int, int, int) {
 	return pageSize, pageIndex, offset
 }
 
+// Cursor is where a page of results ordered by date and ID starts: right after the result with this date and ID
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// String encodes the cursor to be sent to clients
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// CheckCursorPagination returns the page size (DefaultCursorPageSize when not positive, at most MaxPageSize)
+// and the decoded cursor, nil for the first page
+func CheckCursorPagination(pageSize int, cursor string) (int, *Cursor, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultCursorPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	if cursor == "" {
+		return pageSize, nil, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, nil, err
+	}
+	createdAt, id, found := strings.Cut(string(decoded), "|")
+	if !found || id == "" {
+		return 0, nil, errors.New("invalid cursor")
+	}
+	date, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return pageSize, &Cursor{CreatedAt: date, ID: id}, nil
+}
+
 func HashPassword(password string) string {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 	if err != nil {
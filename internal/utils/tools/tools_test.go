@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPagination(t *testing.T) {
+	pageSize, pageIndex, offset := CheckPagination(0, 3)
+	assert.Equal(t, []int{-1, 0, -1}, []int{pageSize, pageIndex, offset})
+	pageSize, pageIndex, offset = CheckPagination(500, 2)
+	assert.Equal(t, []int{100, 2, 200}, []int{pageSize, pageIndex, offset})
+}
+
+func TestCheckCursorPagination(t *testing.T) {
+	pageSize, cursor, err := CheckCursorPagination(0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCursorPageSize, pageSize)
+	assert.Nil(t, cursor)
+
+	pageSize, _, err = CheckCursorPagination(1000, "")
+	assert.NoError(t, err)
+	assert.Equal(t, MaxPageSize, pageSize)
+
+	position := Cursor{CreatedAt: time.Date(2024, 5, 6, 8, 30, 15, 123000000, time.FixedZone("CEST", 2*3600)), ID: "c0ffee"}
+	pageSize, cursor, err = CheckCursorPagination(10, position.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 10, pageSize)
+	assert.True(t, position.CreatedAt.Equal(cursor.CreatedAt))
+	assert.Equal(t, "c0ffee", cursor.ID)
+
+	for _, invalid := range []string{"!!", "bm9waXBl", Cursor{ID: ""}.String()} {
+		_, _, err = CheckCursorPagination(10, invalid)
+		assert.Error(t, err, invalid)
+	}
+}
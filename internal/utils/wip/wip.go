@@ -0,0 +1,35 @@
+package wip
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+
+	"gorm.io/gorm"
+)
+
+// Check checks the WIP limit of a list before the card cardId enters it (cardId is not counted).
+// Beyond the limit, an error is returned when the limit is hard, a warning to pass to the user otherwise
+func Check(context models.Context, db *gorm.DB, list *models.List, cardId string) (string, int, error) {
+	if list.WipLimit <= 0 {
+		return "", http.StatusOK, nil
+	}
+
+	var count int64
+	err := db.Model(&models.Card{}).
+		Where("list_id = ? AND id <> ? AND archived_at IS NULL", list.ID, cardId).
+		Count(&count).Error
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	if int(count) < list.WipLimit {
+		return "", http.StatusOK, nil
+	}
+	if list.WipHard {
+		return "", http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "WipLimitReached"))
+	}
+
+	return fmt.Sprintf("%s (%d/%d)", messages.GetMessage(context.Lang, "WipLimitExceeded"), count+1, list.WipLimit), http.StatusOK, nil
+}
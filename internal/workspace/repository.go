@@ -0,0 +1,486 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"trellode-go/internal/log"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type WorkspaceRepository struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	logService log.LogService
+}
+
+type WorkspaceRepositoryInterface interface {
+	GetWorkspaces(models.Context) ([]*models.Workspace, int, error)
+	GetWorkspace(models.Context, string) (*models.Workspace, int, error)
+	CreateWorkspace(models.Context, *models.Workspace) (string, int, error)
+	UpdateWorkspace(models.Context, *models.Workspace) (int, error)
+	DeleteWorkspace(models.Context, string) (int, error)
+	GetRole(models.Context, string) (string, int, error)
+
+	GetMembers(models.Context, string) ([]*models.WorkspaceMember, int, error)
+	GetMember(models.Context, string, string) (*models.WorkspaceMember, int, error)
+	CreateMember(models.Context, *models.WorkspaceMember) (int, error)
+	UpdateMember(models.Context, *models.WorkspaceMember) (int, error)
+	DeleteMember(models.Context, string, string) (int, error)
+}
+
+func NewWorkspaceRepository(db *gorm.DB, log *zap.Logger, logService log.LogService) WorkspaceRepository {
+	return WorkspaceRepository{
+		db:         db,
+		log:        log,
+		logService: logService,
+	}
+}
+
+// GetWorkspaces returns the workspaces the current user is a member of
+func (repo WorkspaceRepository) GetWorkspaces(context models.Context) ([]*models.Workspace, int, error) {
+	workspaces := []*models.Workspace{}
+	err := repo.db.
+		Where("id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)", context.UserId).
+		Order("name ASC").
+		Find(&workspaces).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return workspaces, http.StatusOK, nil
+}
+
+func (repo WorkspaceRepository) GetWorkspace(context models.Context, id string) (*models.Workspace, int, error) {
+	var workspace models.Workspace
+	err := repo.db.
+		Preload("Members", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Members.User").
+		Where("id = ?", id).
+		First(&workspace).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if workspace.ID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "WorkspaceNotFound"))
+	}
+
+	return &workspace, http.StatusOK, nil
+}
+
+func (repo WorkspaceRepository) CreateWorkspace(context models.Context, workspace *models.Workspace) (string, int, error) {
+	if workspace.DefaultRole == "" {
+		workspace.DefaultRole = models.BoardRoleViewer
+	}
+	if !IsValidDefaultRole(workspace.DefaultRole) {
+		return "", http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidDefaultRole"))
+	}
+
+	// override userId
+	workspace.ID = uuid.NewString()
+	workspace.UserID = context.UserId
+	workspace.Members = nil
+
+	tx := repo.db.Begin()
+
+	err := tx.Create(&workspace).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// creator is admin of the workspace
+	err = tx.Create(&models.WorkspaceMember{
+		WorkspaceID: workspace.ID,
+		UserID:      context.UserId,
+		Role:        models.WorkspaceRoleAdmin,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return "", http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		Action:         "createworkspace",
+		ActionTargetID: workspace.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return "", severity, err
+	}
+
+	tx.Commit()
+
+	return workspace.ID, http.StatusCreated, nil
+}
+
+func (repo WorkspaceRepository) UpdateWorkspace(context models.Context, workspace *models.Workspace) (int, error) {
+	if !IsValidDefaultRole(workspace.DefaultRole) {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidDefaultRole"))
+	}
+
+	workspaceBefore, severity, err := repo.GetWorkspace(context, workspace.ID)
+	if err != nil {
+		return severity, err
+	}
+
+	// what changed?
+	changes := whatChanged(workspaceBefore, workspace)
+	// marshal changes to JSON string
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.Workspace{}).
+		Where("id = ?", workspace.ID).
+		Updates(map[string]interface{}{
+			"name":         workspace.Name,
+			"description":  workspace.Description,
+			"default_role": workspace.DefaultRole,
+			"updated_at":   time.Now(),
+		}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		Action:         "updateworkspace",
+		ActionTargetID: workspace.ID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// DeleteWorkspace removes a workspace and its members, its boards are kept and given back to their creators
+func (repo WorkspaceRepository) DeleteWorkspace(context models.Context, id string) (int, error) {
+	_, severity, err := repo.GetWorkspace(context, id)
+	if err != nil {
+		return severity, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.Board{}).Where("workspace_id = ?", id).Update("workspace_id", nil).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	err = tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceMember{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	err = tx.Where("id = ?", id).Delete(&models.Workspace{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		Action:         "deleteworkspace",
+		ActionTargetID: id,
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+// GetRole returns the role of the current user in a workspace, or an empty string if the user is not a member
+func (repo WorkspaceRepository) GetRole(context models.Context, id string) (string, int, error) {
+	var workspace models.Workspace
+	err := repo.db.Where("id = ?", id).First(&workspace).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+	if workspace.ID == "" {
+		return "", http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "WorkspaceNotFound"))
+	}
+
+	var member models.WorkspaceMember
+	err = repo.db.Where("workspace_id = ? AND user_id = ?", id, context.UserId).First(&member).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+
+	return member.Role, http.StatusOK, nil
+}
+
+func (repo WorkspaceRepository) GetMembers(context models.Context, workspaceId string) ([]*models.WorkspaceMember, int, error) {
+	members := []*models.WorkspaceMember{}
+	err := repo.db.
+		Preload("User").
+		Where("workspace_id = ?", workspaceId).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return members, http.StatusOK, nil
+}
+
+func (repo WorkspaceRepository) GetMember(context models.Context, workspaceId string, userId string) (*models.WorkspaceMember, int, error) {
+	var member models.WorkspaceMember
+	err := repo.db.
+		Preload("User").
+		Where("workspace_id = ? AND user_id = ?", workspaceId, userId).
+		First(&member).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	if member.UserID == "" {
+		return nil, http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "MemberNotFound"))
+	}
+
+	return &member, http.StatusOK, nil
+}
+
+func (repo WorkspaceRepository) CreateMember(context models.Context, member *models.WorkspaceMember) (int, error) {
+	if !IsValidRole(member.Role) {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidWorkspaceRole"))
+	}
+
+	// resolve user from email if no id was given
+	var user models.User
+	var err error
+	if member.UserID != "" {
+		err = repo.db.Where("id = ?", member.UserID).First(&user).Error
+	} else {
+		err = repo.db.Where("email = ?", member.Email).First(&user).Error
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if user.ID == "" {
+		return http.StatusNotFound, errors.New(messages.GetMessage(context.Lang, "UserNotFound"))
+	}
+	member.UserID = user.ID
+
+	// check user is not already a member
+	var existingMember models.WorkspaceMember
+	err = repo.db.Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).First(&existingMember).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusInternalServerError, err
+	}
+	if existingMember.UserID != "" {
+		return http.StatusConflict, errors.New(messages.GetMessage(context.Lang, "WorkspaceMemberAlreadyExists"))
+	}
+
+	member.User = nil
+
+	tx := repo.db.Begin()
+
+	err = tx.Create(&member).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err := repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		Action:         "addworkspacemember",
+		ActionTargetID: member.UserID,
+		Changes:        workspaceChange(member.WorkspaceID),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusCreated, nil
+}
+
+func (repo WorkspaceRepository) UpdateMember(context models.Context, member *models.WorkspaceMember) (int, error) {
+	if !IsValidRole(member.Role) {
+		return http.StatusBadRequest, errors.New(messages.GetMessage(context.Lang, "InvalidWorkspaceRole"))
+	}
+
+	memberBefore, severity, err := repo.GetMember(context, member.WorkspaceID, member.UserID)
+	if err != nil {
+		return severity, err
+	}
+
+	// the creator of a workspace always stays admin
+	isCreator, err := repo.isWorkspaceCreator(member.WorkspaceID, member.UserID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if isCreator && member.Role != models.WorkspaceRoleAdmin {
+		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "WorkspaceCreatorRole"))
+	}
+
+	changes := []*models.LogChange{{
+		Field:   "workspaceid",
+		ToValue: member.WorkspaceID,
+	}}
+	if memberBefore.Role != member.Role {
+		changes = append(changes, &models.LogChange{
+			Field:     "role",
+			FromValue: memberBefore.Role,
+			ToValue:   member.Role,
+		})
+	}
+	// marshal changes to JSON string
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).
+		Updates(map[string]interface{}{"role": member.Role, "updated_at": time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		Action:         "updateworkspacemember",
+		ActionTargetID: member.UserID,
+		Changes:        string(changesJson),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+func (repo WorkspaceRepository) DeleteMember(context models.Context, workspaceId string, userId string) (int, error) {
+	_, severity, err := repo.GetMember(context, workspaceId, userId)
+	if err != nil {
+		return severity, err
+	}
+
+	// the creator of a workspace cannot be removed from it
+	isCreator, err := repo.isWorkspaceCreator(workspaceId, userId)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if isCreator {
+		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "WorkspaceCreatorRole"))
+	}
+
+	tx := repo.db.Begin()
+
+	err = tx.Where("workspace_id = ? AND user_id = ?", workspaceId, userId).Delete(&models.WorkspaceMember{}).Error
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	// log operation
+	_, severity, err = repo.logService.CreateLog(context, tx, &models.Log{
+		UserID:         context.UserId,
+		Action:         "removeworkspacemember",
+		ActionTargetID: userId,
+		Changes:        workspaceChange(workspaceId),
+	})
+	if err != nil {
+		tx.Rollback()
+		return severity, err
+	}
+
+	tx.Commit()
+
+	return http.StatusAccepted, nil
+}
+
+func (repo WorkspaceRepository) isWorkspaceCreator(workspaceId string, userId string) (bool, error) {
+	var workspace models.Workspace
+	err := repo.db.Where("id = ?", workspaceId).First(&workspace).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	return workspace.ID != "" && workspace.UserID == userId, nil
+}
+
+// IsValidRole tells if the given role is one of admin or member
+func IsValidRole(role string) bool {
+	return role == models.WorkspaceRoleAdmin || role == models.WorkspaceRoleMember
+}
+
+// IsValidDefaultRole tells if the given board role can be granted to all members of a workspace
+func IsValidDefaultRole(role string) bool {
+	return role == models.BoardRoleViewer || role == models.BoardRoleEditor
+}
+
+// workspaceChange keeps track of the workspace in member logs, as logs only reference boards
+func workspaceChange(workspaceId string) string {
+	changesJson, _ := json.Marshal([]*models.LogChange{{
+		Field:   "workspaceid",
+		ToValue: workspaceId,
+	}})
+	return string(changesJson)
+}
+
+func whatChanged(workspaceBefore *models.Workspace, workspaceAfter *models.Workspace) []*models.LogChange {
+	changes := []*models.LogChange{}
+
+	if workspaceBefore.Name != workspaceAfter.Name {
+		changes = append(changes, &models.LogChange{
+			Field:     "name",
+			FromValue: workspaceBefore.Name,
+			ToValue:   workspaceAfter.Name,
+		})
+	}
+	if workspaceBefore.Description != workspaceAfter.Description {
+		changes = append(changes, &models.LogChange{
+			Field:     "description",
+			FromValue: workspaceBefore.Description,
+			ToValue:   workspaceAfter.Description,
+		})
+	}
+	if workspaceBefore.DefaultRole != workspaceAfter.DefaultRole {
+		changes = append(changes, &models.LogChange{
+			Field:     "defaultrole",
+			FromValue: workspaceBefore.DefaultRole,
+			ToValue:   workspaceAfter.DefaultRole,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,123 @@
+package workspace
+
+import (
+	"errors"
+	"net/http"
+	"trellode-go/internal/models"
+	"trellode-go/internal/utils/messages"
+)
+
+type WorkspaceServiceInterface interface {
+	GetWorkspaces(models.Context) ([]*models.Workspace, int, error)
+	GetWorkspace(models.Context, string) (*models.Workspace, int, error)
+	CreateWorkspace(models.Context, *models.Workspace) (string, int, error)
+	UpdateWorkspace(models.Context, *models.Workspace) (int, error)
+	DeleteWorkspace(models.Context, string) (int, error)
+
+	GetMembers(models.Context, string) ([]*models.WorkspaceMember, int, error)
+	CreateMember(models.Context, *models.WorkspaceMember) (int, error)
+	UpdateMember(models.Context, *models.WorkspaceMember) (int, error)
+	DeleteMember(models.Context, string, string) (int, error)
+
+	CheckWorkspaceRole(models.Context, string, string) (int, error)
+}
+
+type WorkspaceService struct {
+	repo WorkspaceRepositoryInterface
+}
+
+// NewWorkspaceService returns a service to manipulate workspaces and their members
+func NewWorkspaceService(repo WorkspaceRepositoryInterface) WorkspaceService {
+	return WorkspaceService{
+		repo: repo,
+	}
+}
+
+func (s WorkspaceService) GetWorkspaces(context models.Context) ([]*models.Workspace, int, error) {
+	return s.repo.GetWorkspaces(context)
+}
+
+func (s WorkspaceService) GetWorkspace(context models.Context, id string) (*models.Workspace, int, error) {
+	severity, err := s.CheckWorkspaceRole(context, id, models.WorkspaceRoleMember)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.GetWorkspace(context, id)
+}
+
+func (s WorkspaceService) CreateWorkspace(context models.Context, workspace *models.Workspace) (string, int, error) {
+	return s.repo.CreateWorkspace(context, workspace)
+}
+
+func (s WorkspaceService) UpdateWorkspace(context models.Context, workspace *models.Workspace) (int, error) {
+	severity, err := s.CheckWorkspaceRole(context, workspace.ID, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.UpdateWorkspace(context, workspace)
+}
+
+func (s WorkspaceService) DeleteWorkspace(context models.Context, id string) (int, error) {
+	severity, err := s.CheckWorkspaceRole(context, id, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.DeleteWorkspace(context, id)
+}
+
+func (s WorkspaceService) GetMembers(context models.Context, workspaceId string) ([]*models.WorkspaceMember, int, error) {
+	severity, err := s.CheckWorkspaceRole(context, workspaceId, models.WorkspaceRoleMember)
+	if err != nil {
+		return nil, severity, err
+	}
+
+	return s.repo.GetMembers(context, workspaceId)
+}
+
+func (s WorkspaceService) CreateMember(context models.Context, member *models.WorkspaceMember) (int, error) {
+	severity, err := s.CheckWorkspaceRole(context, member.WorkspaceID, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.CreateMember(context, member)
+}
+
+func (s WorkspaceService) UpdateMember(context models.Context, member *models.WorkspaceMember) (int, error) {
+	severity, err := s.CheckWorkspaceRole(context, member.WorkspaceID, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.UpdateMember(context, member)
+}
+
+func (s WorkspaceService) DeleteMember(context models.Context, workspaceId string, userId string) (int, error) {
+	// any member can leave a workspace, only admins can remove someone else
+	minimumRole := models.WorkspaceRoleAdmin
+	if userId == context.UserId {
+		minimumRole = models.WorkspaceRoleMember
+	}
+	severity, err := s.CheckWorkspaceRole(context, workspaceId, minimumRole)
+	if err != nil {
+		return severity, err
+	}
+
+	return s.repo.DeleteMember(context, workspaceId, userId)
+}
+
+// CheckWorkspaceRole returns an error if the current user does not have at least minimumRole in the workspace
+func (s WorkspaceService) CheckWorkspaceRole(context models.Context, workspaceId string, minimumRole string) (int, error) {
+	role, severity, err := s.repo.GetRole(context, workspaceId)
+	if err != nil {
+		return severity, err
+	}
+	if role == "" || (minimumRole == models.WorkspaceRoleAdmin && role != models.WorkspaceRoleAdmin) {
+		return http.StatusForbidden, errors.New(messages.GetMessage(context.Lang, "InsufficientWorkspaceRole"))
+	}
+
+	return http.StatusOK, nil
+}